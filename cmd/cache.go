@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ghp/pkg/cache"
+	"ghp/pkg/i18n"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: i18n.T("cache.short"),
+	Long:  i18n.T("cache.long"),
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: i18n.T("cache.list.short"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := loadCacheEntries("")
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println(i18n.T("cache.empty"))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("cache.list.header"))
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Namespace, e.Program, describeEntry(e), formatBytes(e.Size), e.ModTime.Format("2006-01-02 15:04"))
+		}
+		return w.Flush()
+	},
+}
+
+var cacheShowCmd = &cobra.Command{
+	Use:   "show <program>",
+	Short: i18n.T("cache.show.short"),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := loadCacheEntries(args[0])
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println(i18n.T("cache.show.none", args[0]))
+			return nil
+		}
+
+		for i, e := range entries {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("== [%s] %s (%s)\n", e.Namespace, describeEntry(e), e.ModTime.Format("2006-01-02 15:04"))
+			fmt.Println(entryContent(e))
+		}
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [program]",
+	Short: i18n.T("cache.clear.short"),
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			dir, err := cache.Dir()
+			if err != nil {
+				return err
+			}
+			// 命中统计 stats.json 也在缓存目录下，一并清除
+			if err := os.RemoveAll(dir); err != nil {
+				return err
+			}
+			fmt.Println(i18n.T("cache.clear.all", dir))
+			return nil
+		}
+
+		entries, err := loadCacheEntries(args[0])
+		if err != nil {
+			return err
+		}
+		removed := 0
+		for _, e := range entries {
+			store, err := cache.Open(e.Namespace)
+			if err != nil {
+				return err
+			}
+			if err := store.Delete(e.Key); err != nil {
+				return err
+			}
+			removed++
+		}
+		fmt.Println(i18n.T("cache.clear.program", args[0], removed))
+		return nil
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: i18n.T("cache.stats.short"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stats, err := cache.LoadStats()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("cache.stats.header"))
+		var totalEntries int
+		var totalBytes int64
+		var total cache.Counter
+		for _, ns := range cache.Namespaces {
+			store, err := cache.Open(ns)
+			if err != nil {
+				return err
+			}
+			entries, err := store.Entries()
+			if err != nil {
+				return err
+			}
+			var size int64
+			for _, e := range entries {
+				size += e.Size
+			}
+			c := stats[ns]
+			fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%.1f%%\n", ns, len(entries), formatBytes(size), c.Hits, c.Misses, c.HitRate()*100)
+
+			totalEntries += len(entries)
+			totalBytes += size
+			total.Hits += c.Hits
+			total.Misses += c.Misses
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%.1f%%\n", i18n.T("cache.stats.total"), totalEntries, formatBytes(totalBytes), total.Hits, total.Misses, total.HitRate()*100)
+		return w.Flush()
+	},
+}
+
+// loadCacheEntries 读取所有命名空间的缓存项，program 不为空时只返回该程序的缓存
+func loadCacheEntries(program string) ([]cache.Entry, error) {
+	var result []cache.Entry
+	for _, ns := range cache.Namespaces {
+		store, err := cache.Open(ns)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := store.Entries()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if program == "" || e.Program == program {
+				result = append(result, e)
+			}
+		}
+	}
+	return result, nil
+}
+
+// cachedValue 各命名空间缓存值的公共字段
+type cachedValue struct {
+	Path      string    `json:"path"`
+	Output    string    `json:"output"`
+	UsedCmd   string    `json:"used_cmd"`
+	Help      []string  `json:"help"`
+	Version   []string  `json:"version"`
+	Mode      string    `json:"mode"`
+	Model     string    `json:"model"`
+	Answer    string    `json:"answer"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// describeEntry 生成缓存项的一行摘要
+func describeEntry(e cache.Entry) string {
+	var v cachedValue
+	json.Unmarshal(e.Value, &v)
+	switch e.Namespace {
+	case cache.NamespaceProbes:
+		return fmt.Sprintf("%s (%s)", v.UsedCmd, v.Path)
+	case cache.NamespaceCommands:
+		version := strings.Join(v.Version, " ")
+		if version == "" {
+			version = "NONE"
+		}
+		return fmt.Sprintf("%s | %s", strings.Join(v.Help, " "), version)
+	case cache.NamespaceAnswers:
+		if v.Model == "" {
+			return v.Mode
+		}
+		return fmt.Sprintf("%s · %s", v.Mode, v.Model)
+	}
+	return e.Key
+}
+
+// entryContent 返回缓存项的完整内容
+func entryContent(e cache.Entry) string {
+	var v cachedValue
+	json.Unmarshal(e.Value, &v)
+	switch e.Namespace {
+	case cache.NamespaceProbes:
+		return strings.TrimRight(v.Output, "\n")
+	case cache.NamespaceAnswers:
+		return strings.TrimRight(v.Answer, "\n")
+	}
+	return describeEntry(e)
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheListCmd, cacheShowCmd, cacheClearCmd, cacheStatsCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/i18n"
+	"ghp/pkg/render"
+)
+
+// lineReader 追问模式的输入
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+// termReader 终端输入，支持行编辑和上下键切换历史问题
+type termReader struct {
+	fd int
+	t  *term.Terminal
+}
+
+func newTermReader(fd int) *termReader {
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "> ")
+	if width, height, err := term.GetSize(fd); err == nil {
+		t.SetSize(width, height)
+	}
+	return &termReader{fd: fd, t: t}
+}
+
+// ReadLine 仅在读取输入时进入 raw 模式，回答期间仍可以用 Ctrl+C 中断
+func (r *termReader) ReadLine() (string, error) {
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(r.fd, state)
+	return r.t.ReadLine()
+}
+
+// pipeReader 非终端输入 (如管道)，逐行读取
+type pipeReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *pipeReader) ReadLine() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// runChat 在回答之后进入追问模式，输入 exit 或按 Ctrl+D 退出
+// 回答期间按 Ctrl+C 只中断当前回答，会话继续
+func runChat(ctx context.Context, aiClient *ai.Client) {
+	conv, err := aiClient.Conversation()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var in lineReader
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		in = newTermReader(fd)
+	} else {
+		in = &pipeReader{scanner: bufio.NewScanner(os.Stdin)}
+	}
+
+	fmt.Println()
+	fmt.Println(i18n.T("chat.intro"))
+	for ctx.Err() == nil {
+		line, err := in.ReadLine()
+		if err != nil {
+			return
+		}
+		question := strings.TrimSpace(line)
+		switch question {
+		case "":
+			continue
+		case "exit", "quit":
+			return
+		}
+
+		askCtx, stop := context.WithCancel(ctx)
+		interruptAnswer.Store(&stop)
+		spinner := render.StartSpinner(i18n.T("chat.spinner"))
+		err = conv.Ask(askCtx, useStream, question, func(delta string) {
+			spinner.Stop()
+			fmt.Print(delta)
+		})
+		spinner.Stop()
+		interruptAnswer.Store(nil)
+		interrupted := askCtx.Err() != nil && ctx.Err() == nil
+		stop()
+		fmt.Println()
+		switch {
+		case interrupted:
+			fmt.Println(i18n.T("chat.interrupted"))
+		case err != nil:
+			reportAIError(i18n.T("chat.err"), err)
+		}
+	}
+}
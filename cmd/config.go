@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/config"
+	"ghp/pkg/i18n"
+)
+
+var (
+	showRedacted    bool
+	showSecrets     bool
+	validateProfile string
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: i18n.T("config.short"),
+	Long:  i18n.T("config.long"),
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: i18n.T("config.init.short"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		in := bufio.NewReader(os.Stdin)
+		if _, err := os.Stat(path); err == nil {
+			if !confirm(in, i18n.T("config.init.overwrite", path)) {
+				fmt.Println(i18n.T("config.init.cancelled"))
+				return nil
+			}
+		}
+
+		cfg := &config.Config{}
+		cfg.Provider = strings.ToLower(prompt(in, i18n.T("config.init.provider")+" ("+strings.Join(config.Providers, "/")+")", config.ProviderOpenAI))
+		if !slices.Contains(config.Providers, cfg.Provider) {
+			return errors.New(i18n.T("config.init.err_provider", cfg.Provider))
+		}
+		defaults := &config.Config{Provider: cfg.Provider}
+		defaults.ApplyDefaults()
+
+		if baseURL := prompt(in, i18n.T("config.init.base_url"), defaults.BaseURL); baseURL != defaults.BaseURL {
+			cfg.BaseURL = baseURL
+		}
+		if cfg.Provider != config.ProviderOllama {
+			cfg.APIKey = promptSecret(in, "API Key")
+			if cfg.APIKey == "" {
+				fmt.Println(i18n.T("config.init.hint_api_key"))
+			}
+		}
+		modelHint := defaults.Model
+		if modelHint == "" {
+			modelHint = i18n.T("config.init.auto_model")
+		}
+		if model := prompt(in, i18n.T("config.init.model"), modelHint); model != modelHint {
+			cfg.Model = model
+		}
+		if lang := prompt(in, i18n.T("config.init.language"), i18n.Language()); lang != i18n.Detect() {
+			cfg.Language = lang
+		}
+
+		if err := config.Save(path, cfg); err != nil {
+			return err
+		}
+		fmt.Println(i18n.T("config.init.written", path))
+		fmt.Println(i18n.T("config.init.hint_validate"))
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: i18n.T("config.get.short"),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		cfg.ApplyDefaults()
+		value, err := cfg.GetValue(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: i18n.T("config.set.short"),
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		if err := config.SetFileValue(path, args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Println(i18n.T("config.set.done", args[0], path))
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: i18n.T("config.show.short"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		cfg.ApplyDefaults()
+
+		if len(cfg.Sources) == 0 {
+			fmt.Println(i18n.T("config.show.no_file"))
+		}
+		for _, src := range cfg.Sources {
+			fmt.Println(i18n.T("config.show.loaded", src))
+		}
+		if showRedacted && !showSecrets {
+			cfg = cfg.Redacted()
+		}
+		data, err := yaml.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: i18n.T("config.validate.short"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if validateProfile != "" {
+			if err := cfg.ApplyProfile(validateProfile); err != nil {
+				return err
+			}
+		}
+		if err := cfg.Finalize(); err != nil {
+			return err
+		}
+
+		model := cfg.Model
+		if model == "" {
+			model = i18n.T("config.validate.auto_model")
+		}
+		fmt.Println(i18n.T("config.validate.summary", cfg.Provider, cfg.BaseURL, model))
+
+		timeout := cfg.Timeouts.Request
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_, err = newProvider(cfg).Complete(ctx, ai.Request{
+			Model:       cfg.Model,
+			Messages:    []ai.Message{{Role: ai.RoleUser, Content: "ping, reply with OK"}},
+			Temperature: *cfg.Temperature,
+		})
+		if err == nil {
+			fmt.Println(i18n.T("config.validate.ok"))
+			return nil
+		}
+
+		switch ai.ClassifyError(err) {
+		case ai.ErrorAuth:
+			fmt.Println(i18n.T("config.validate.err_auth"))
+		case ai.ErrorModel:
+			fmt.Println(i18n.T("config.validate.err_model", model))
+		case ai.ErrorNetwork:
+			fmt.Println(i18n.T("config.validate.err_network", cfg.BaseURL))
+		default:
+			if errors.Is(err, context.DeadlineExceeded) {
+				fmt.Println(i18n.T("config.validate.err_timeout", timeout))
+			}
+		}
+		return err
+	},
+}
+
+// prompt 读取一行输入，直接回车时使用默认值
+func prompt(in *bufio.Reader, label, def string) string {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", label, def)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+	line, _ := in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def
+	}
+	return line
+}
+
+// promptSecret 读取敏感输入，终端下不回显
+func promptSecret(in *bufio.Reader, label string) string {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return prompt(in, label, "")
+	}
+	fmt.Printf("%s %s: ", label, i18n.T("config.prompt.hidden"))
+	secret, _ := term.ReadPassword(fd)
+	fmt.Println()
+	return strings.TrimSpace(string(secret))
+}
+
+// confirm 询问是/否，默认为否
+func confirm(in *bufio.Reader, question string) bool {
+	answer := strings.ToLower(prompt(in, question+" [y/N]", ""))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&showRedacted, "redacted", true, i18n.T("config.flag.redacted"))
+	configShowCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, i18n.T("config.flag.show_secrets"))
+	configShowCmd.MarkFlagsMutuallyExclusive("redacted", "show-secrets")
+	configValidateCmd.Flags().StringVarP(&validateProfile, "profile", "p", "", i18n.T("config.flag.profile"))
+
+	for _, c := range []*cobra.Command{configInitCmd, configGetCmd, configSetCmd, configShowCmd, configValidateCmd} {
+		c.SilenceUsage = true
+	}
+	configCmd.AddCommand(configInitCmd, configGetCmd, configSetCmd, configShowCmd, configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
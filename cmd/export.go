@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/config"
+	"ghp/pkg/executor"
+	"ghp/pkg/i18n"
+	"ghp/pkg/render"
+)
+
+var (
+	exportDir    string
+	exportFormat string
+)
+
+var exportCmd = &cobra.Command{
+	Use:          "export <program...>",
+	Short:        i18n.T("export.short"),
+	Long:         i18n.T("export.long"),
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(render.Formats, exportFormat) {
+			return errors.New(i18n.T("render.err_format", exportFormat, strings.Join(render.Formats, ", ")))
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go gracefulShutdown(cancel)
+
+		cfg, err := loadConfig(cmd, ai.ModeLookup)
+		if err != nil {
+			return err
+		}
+		aiClient := newAIClient(cfg)
+		if !noCache {
+			aiClient.EnableAnswerCache(ai.DefaultAnswerTTL, refreshCache)
+		}
+
+		if err := os.MkdirAll(exportDir, 0o755); err != nil {
+			return err
+		}
+
+		var entries []render.IndexEntry
+		failed := 0
+		for i, program := range args {
+			fmt.Printf("[%d/%d] %s\n", i+1, len(args), program)
+			entry, err := exportProgram(ctx, cfg, aiClient, program)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				fmt.Println(i18n.T("export.skipped", err))
+				failed++
+				continue
+			}
+			fmt.Println(i18n.T("export.written", filepath.Join(exportDir, entry.File)))
+			entries = append(entries, entry)
+		}
+
+		if len(entries) > 0 {
+			index := filepath.Join(exportDir, "index"+render.Ext(exportFormat))
+			if err := writeExportFile(index, func(f *os.File) error {
+				return render.WriteIndex(f, exportFormat, i18n.T("export.index_title"), entries)
+			}); err != nil {
+				return err
+			}
+			fmt.Println(i18n.T("export.done", len(entries), index))
+		}
+		if failed > 0 {
+			return errors.New(i18n.T("export.failed", failed))
+		}
+		return nil
+	},
+}
+
+// exportProgram 生成单个命令的速查表文档
+func exportProgram(ctx context.Context, cfg *config.Config, aiClient *ai.Client, program string) (render.IndexEntry, error) {
+	name := filepath.Base(program)
+	entry := render.IndexEntry{Name: name, File: name + render.Ext(exportFormat)}
+
+	cmdPath, err := executor.CheckCommandExists(program)
+	if err != nil {
+		return entry, err
+	}
+	probe, err := probeProgram(ctx, aiClient, program, cmdPath, true)
+	if err != nil {
+		return entry, err
+	}
+
+	spinner := render.StartSpinner(i18n.T("export.spinner"))
+	answer, err := aiClient.AnalyzeHelpDoc(ctx, useStream, useConcise, false, "", probe.usedCmd, probe.help, probe.version, cmdPath, tldrExamples(findTLDR(cfg, program, "")))
+	spinner.Stop()
+	if err != nil {
+		return entry, err
+	}
+	if sheet, ok := answer.(*ai.CheatSheet); ok {
+		entry.Summary = sheet.Summary
+		entry.Version = sheet.Version
+	}
+
+	err = writeExportFile(filepath.Join(exportDir, entry.File), func(f *os.File) error {
+		return render.WriteDocument(f, exportFormat, name, answer)
+	})
+	return entry, err
+}
+
+// writeExportFile 创建文件并写入内容
+func writeExportFile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportDir, "output", "o", "ghp-export", i18n.T("export.flag.output"))
+	exportCmd.Flags().StringVar(&exportFormat, "format", render.FormatMarkdown, i18n.T("export.flag.format"))
+	exportCmd.Flags().BoolVarP(&useConcise, "concise", "c", true, i18n.T("export.flag.concise"))
+	addAIFlags(exportCmd)
+	rootCmd.AddCommand(exportCmd)
+}
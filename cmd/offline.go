@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/i18n"
+	"ghp/pkg/offline"
+	"ghp/pkg/tldr"
+)
+
+// offlineAnswer AI 服务不可用时，由本地解析的帮助文档生成回答，本地有 tldr 页面时使用其中的示例
+// 降级原因输出到标准错误，不影响 JSON 和文档输出
+func offlineAnswer(reason error, subQuery, helpOutput, verOutput, cmdPath string, page *tldr.Page) ai.Answer {
+	fmt.Fprintln(os.Stderr, i18n.T("offline.reason", reason))
+	if subQuery != "" {
+		if card, ok := offline.Subcommand(helpOutput, subQuery); ok {
+			if page != nil {
+				card.Examples = tldrExamples(page)
+			}
+			return card
+		}
+		// 帮助文档中找不到该子命令，但有它的 tldr 页面
+		if page != nil {
+			return &ai.SubcommandCard{Kind: ai.SubcommandOK, Subcommand: subQuery, Summary: page.Description, Options: []ai.Option{}, Examples: tldrExamples(page), Offline: true}
+		}
+		fmt.Fprintln(os.Stderr, i18n.T("offline.query_ignored", subQuery))
+	}
+	if verOutput == i18n.T("probe.no_version") {
+		verOutput = ""
+	}
+	sheet := offline.CheatSheet(helpOutput, verOutput, cmdPath)
+	if page != nil {
+		sheet.Examples = tldrExamples(page)
+		if sheet.Summary == "" {
+			sheet.Summary = page.Description
+		}
+	}
+	return sheet
+}
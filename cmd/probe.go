@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/executor"
+	"ghp/pkg/i18n"
+)
+
+// errNoHelp 已尝试 AI 推荐指令及标准参数，仍无法获取帮助文档
+var errNoHelp error = noHelpError{}
+
+type noHelpError struct{}
+
+func (noHelpError) Error() string {
+	return i18n.T("probe.err_no_help")
+}
+
+// probeResult 程序的帮助/版本输出
+type probeResult struct {
+	help    string
+	usedCmd string // 实际执行的帮助指令
+	version string
+	aiErr   error // 询问 AI 推荐指令时服务不可用，已改用标准参数
+}
+
+// probeProgram 获取程序的帮助文档，withVersion 为 true 时同时获取版本信息
+// 帮助文档在 --help 等命令的输出之外还会参考 man/info 手册，按质量选择或组合
+// 帮助/版本输出按二进制文件指纹缓存，命中缓存时无需询问 AI 和执行命令
+// aiClient 为 nil 或 AI 服务不可用时只尝试 --help、--version 等标准参数
+func probeProgram(ctx context.Context, aiClient *ai.Client, program, cmdPath string, withVersion bool) (probeResult, error) {
+	res := probeResult{usedCmd: program}
+
+	// 获取查询指令 (Help & Version)，仅在缓存未命中时询问
+	var helpCmdArgs, verCmdArgs []string
+	resolved := false
+	resolveProbeCmds := func() error {
+		if resolved {
+			return nil
+		}
+		if aiClient == nil {
+			helpCmdArgs, verCmdArgs, _ = ai.KnownHelpCommand(program)
+			resolved = true
+			return nil
+		}
+		var err error
+		helpCmdArgs, verCmdArgs, err = aiClient.GetHelpCommand(ctx, program)
+		if ai.Unavailable(err) {
+			res.aiErr = err
+			err = nil
+		}
+		helpCmdArgs = allowedProbe(program, cmdPath, helpCmdArgs)
+		verCmdArgs = allowedProbe(program, cmdPath, verCmdArgs)
+		resolved = err == nil
+		return err
+	}
+
+	// 执行帮助命令
+	hOut, hUsed, success := executor.CachedProbe(cmdPath, executor.ProbeHelp)
+	if !success {
+		if err := resolveProbeCmds(); err != nil {
+			return res, fmt.Errorf(i18n.T("probe.err_commands"), err)
+		}
+		hOut, hUsed, success = executor.RunCommandWithRetry(
+			ctx, helpCmdArgs, [][]string{{"--help"}, {"-h"}, {"help"}}, program,
+		)
+		hOut, hUsed, success = withManuals(ctx, program, cmdPath, hOut, hUsed, success)
+		if success {
+			executor.StoreProbe(cmdPath, executor.ProbeHelp, hOut, hUsed)
+		}
+	}
+	if !success {
+		return res, errNoHelp
+	}
+	res.help = hOut
+	res.usedCmd = hUsed
+
+	// 执行版本命令
+	if withVersion {
+		out, _, success := executor.CachedProbe(cmdPath, executor.ProbeVersion)
+		if !success && resolveProbeCmds() == nil {
+			var used string
+			out, used, success = executor.RunCommandWithRetry(
+				ctx, verCmdArgs, [][]string{{"--version"}, {"-v"}, {"version"}}, program,
+			)
+			if success {
+				executor.StoreProbe(cmdPath, executor.ProbeVersion, out, used)
+			}
+		}
+		if success {
+			res.version = out
+		} else {
+			res.version = i18n.T("probe.no_version")
+		}
+	}
+	return res, nil
+}
+
+// withManuals 结合 man/info 手册选择帮助文档，--help 失败时手册也可以单独作为帮助文档
+// Shell 函数、别名等没有可执行文件的命令不查询手册
+func withManuals(ctx context.Context, program, cmdPath, out, used string, success bool) (string, string, bool) {
+	if !filepath.IsAbs(cmdPath) {
+		return out, used, success
+	}
+	var sources []executor.HelpSource
+	if success {
+		sources = append(sources, executor.HelpSource{Command: used, Text: out})
+	}
+	sources = append(sources, executor.ManualPages(ctx, program)...)
+	best, ok := executor.SelectHelp(sources)
+	if !ok {
+		return out, used, success
+	}
+	return best.Text, best.Command, true
+}
+
+// allowedProbe 校验 AI 推荐的探测命令，不符合策略时提示并丢弃，改用标准参数
+func allowedProbe(program, cmdPath string, args []string) []string {
+	if err := executor.CheckProbe(program, cmdPath, args); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("probe.rejected", strings.Join(args, " "), err))
+		return nil
+	}
+	return args
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/config"
+	"ghp/pkg/i18n"
+)
+
+var promptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: i18n.T("prompts.short"),
+	Long:  i18n.T("prompts.long"),
+}
+
+var promptsDumpCmd = &cobra.Command{
+	Use:          "dump [name...]",
+	Short:        i18n.T("prompts.dump.short"),
+	Long:         i18n.T("prompts.dump.long"),
+	ValidArgs:    ai.PromptNames,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := config.PromptDir()
+		if err != nil {
+			return err
+		}
+		names := args
+		if len(names) == 0 {
+			names = ai.PromptNames
+		}
+
+		for i, name := range names {
+			text, source, err := ai.PromptTemplate(dir, name)
+			if err != nil {
+				return err
+			}
+			if source == "" {
+				source = i18n.T("prompts.builtin")
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Fprintln(os.Stderr, i18n.T("prompts.header", name, source))
+			fmt.Print(text)
+		}
+		return nil
+	},
+}
+
+func init() {
+	promptsCmd.AddCommand(promptsDumpCmd)
+	rootCmd.AddCommand(promptsCmd)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"ghp/pkg/ai"
+	"ghp/pkg/config"
+	"ghp/pkg/risk"
+)
+
+// newRiskEngine 创建包含内置规则和配置文件规则的风险规则引擎
+// 配置中的规则已在 Finalize 时校验过，这里出错时退回只使用内置规则
+func newRiskEngine(cfg *config.Config) *risk.Engine {
+	engine, err := risk.NewEngine(cfg.RiskRules)
+	if err != nil {
+		engine, _ = risk.NewEngine(nil)
+	}
+	return engine
+}
+
+// assessRisk 用本地规则校正模型给出的风险评估，返回校正前模型给出的等级
+// 规则只会调高等级，避免模型低估危险命令；模型返回无效等级时以规则为准
+func assessRisk(engine *risk.Engine, command string, r *ai.Risk) string {
+	if risk.Rank(r.Level) < 0 {
+		r.Level = ""
+	}
+	modelLevel := r.Level
+	matches := engine.Check(command)
+	for _, m := range matches {
+		r.Rules = append(r.Rules, m.Reason)
+	}
+	r.Level = risk.Max(r.Level, risk.Level(matches))
+	return modelLevel
+}
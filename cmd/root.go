@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -13,6 +17,14 @@ import (
 	"ghp/pkg/ai"
 	"ghp/pkg/config"
 	"ghp/pkg/executor"
+	"ghp/pkg/i18n"
+	"ghp/pkg/render"
+)
+
+// 输出格式
+const (
+	outputText = "text" // 在终端中排版展示
+	outputJSON = "json" // 输出结构化 JSON
 )
 
 var (
@@ -21,23 +33,67 @@ var (
 	forceMode    bool
 	analyzeMode  bool
 	generateMode bool
+	chatMode     bool
+	runMode      bool
+	noCache      bool
+	refreshCache bool
+
+	outputFormat    string
+	docFormat       string
+	langFlag        string
+	profileFlag     string
+	providerFlag    string
+	modelFlag       string
+	temperatureFlag float32
 )
 
+// rootCmd 未匹配到子命令时，默认将参数作为要查询的程序处理
 var rootCmd = &cobra.Command{
 	Use:   "ghp [command] [subcommand...]",
 	Short: "AI powered CLI helper",
 	Long:  `ghp is a CLI tool that uses AI to explain commands and provide usage examples.`,
 	Args:  cobra.MinimumNArgs(1),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applyLanguage(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// 0. 参数互斥检查
+		if outputFormat != outputText && outputFormat != outputJSON {
+			fmt.Println(i18n.T("root.err_output_format", outputFormat, outputText, outputJSON))
+			return
+		}
+		if docFormat != "" {
+			if !slices.Contains(render.Formats, docFormat) {
+				fmt.Println(i18n.T("root.err_doc_format", docFormat, strings.Join(render.Formats, ", ")))
+				return
+			}
+			if outputFormat == outputJSON {
+				fmt.Println(i18n.T("root.err_format_json"))
+				return
+			}
+		}
+		// 追问模式的回答为纯文本，只能在终端排版输出时使用
+		if chatMode && (outputFormat != outputText || docFormat != "") {
+			fmt.Println(i18n.T("root.err_chat_format"))
+			return
+		}
+		// 执行命令需要与用户交互确认，只能用于生成模式的终端输出
+		if runMode && !generateMode {
+			fmt.Println(i18n.T("root.err_run_mode"))
+			return
+		}
+		if runMode && (outputFormat != outputText || docFormat != "") {
+			fmt.Println(i18n.T("root.err_run_format"))
+			return
+		}
 		// 解析模式和生成模式互斥
 		if analyzeMode && generateMode {
-			fmt.Println("错误: 无法同时使用解析模式 (-a) 和生成模式 (-g)。请只选择一种操作。")
+			fmt.Println(i18n.T("root.err_analyze_generate"))
 			return
 		}
 		// 强制模式不能与解析或生成模式混用（因为这两种模式依赖准确的帮助文档上下文）
 		if forceMode && (analyzeMode || generateMode) {
-			fmt.Println("错误: 强制模式 (-f) 仅适用于普通查询，不能与解析 (-a) 或生成 (-g) 模式混用。")
+			fmt.Println(i18n.T("root.err_force_mode"))
 			return
 		}
 
@@ -50,15 +106,33 @@ var rootCmd = &cobra.Command{
 		ctx, cancel := context.WithCancel(context.Background())
 		go gracefulShutdown(cancel)
 
-		// 1. 加载配置
-		cfg, err := config.Load()
+		// 1. 加载配置 (不同模式可以使用不同的配置档)
+		mode := ai.ModeLookup
+		if analyzeMode {
+			mode = ai.ModeAnalyze
+		} else if generateMode {
+			mode = ai.ModeGenerate
+		}
+		// 常规查询在配置不可用 (如未设置 API Key) 时降级为离线速查表，解析和生成模式必须使用 AI
+		var offlineErr error
+		cfg, err := loadConfig(cmd, mode)
 		if err != nil {
-			fmt.Println(err)
-			return
+			if mode != ai.ModeLookup {
+				fmt.Println(err)
+				return
+			}
+			offlineErr = err
 		}
 
-		// 2. 初始化 AI 客户端
-		aiClient := ai.NewClient(cfg.NewClientConfig(), cfg.Model)
+		// 2. 初始化 AI 客户端，离线时为 nil
+		var aiClient *ai.Client
+		if offlineErr == nil {
+			aiClient = newAIClient(cfg)
+			if !noCache {
+				// 缓存不可用时仅影响性能，不中断查询
+				aiClient.EnableAnswerCache(ai.DefaultAnswerTTL, refreshCache)
+			}
+		}
 
 		// 3. 检查命令是否存在
 		cmdPath, err := executor.CheckCommandExists(program)
@@ -68,60 +142,48 @@ var rootCmd = &cobra.Command{
 			// 如果是分析模式或生成模式，必须要求命令存在
 			if analyzeMode || generateMode {
 				fmt.Println(err)
-				fmt.Println("错误: 无法处理未安装的命令。我们需要本地帮助文档来确保解释/生成的准确性。")
+				fmt.Println(i18n.T("root.err_missing_program"))
 				return
 			}
 			if !forceMode {
 				fmt.Println(err)
-				fmt.Println("提示: 使用 -f 或 --force 参数可以强制查询未安装的命令")
+				fmt.Println(i18n.T("root.hint_force"))
 				return
 			}
 			isMissing = true
-			cmdPath = "该命令尚未安装"
+			cmdPath = i18n.T("root.not_installed")
 		}
 
 		var helpOutput, verOutput string
 		usedCmd := program
 
 		// 4-6. 仅在命令存在时执行获取帮助逻辑
+		// 版本信息仅精简模式需要，且不在分析/生成模式下获取
 		if !isMissing {
-			// 4. 获取查询指令 (Help & Version)
-			helpCmdArgs, verCmdArgs, err := aiClient.GetHelpCommand(ctx, program)
-			if err != nil {
-				fmt.Println("获取查询指令失败:", err)
-				return
-			}
-
-			// 5. 执行帮助命令
-			hOut, hUsed, success := executor.RunCommandWithRetry(
-				ctx, helpCmdArgs, [][]string{{"--help"}, {"-h"}, {"help"}}, program,
-			)
-			if !success {
+			probe, err := probeProgram(ctx, aiClient, program, cmdPath, useConcise && !analyzeMode && !generateMode)
+			switch {
+			case errors.Is(err, errNoHelp):
 				// 如果开启了强制模式，即使运行失败也尝试降级处理
 				// 这对于 Windows 上存在的 Store Redirector (空壳 exe) 很有用
 				if forceMode {
-					fmt.Println("警告: 无法获取命令帮助文档，将转为强制模式进行查询。")
+					fmt.Println(i18n.T("root.warn_force_fallback"))
 					isMissing = true
-					cmdPath = "检测到命令但无法运行"
+					cmdPath = i18n.T("root.cannot_run")
 				} else {
-					fmt.Println("无法获取命令帮助文档。已尝试 AI 推荐指令及标准参数。")
-					fmt.Println("提示: 命令可能无法正常运行（如 Windows 应用商店别名），请尝试使用 -f 或 --force 参数强制查询。")
+					fmt.Println(i18n.T("root.err_no_help"))
+					fmt.Println(i18n.T("root.hint_no_help"))
 					return
 				}
-			} else {
-				helpOutput = hOut
-				usedCmd = hUsed
-			}
-
-			// 6. 执行版本命令 (仅精简模式需尝试，且不在分析/生成模式下)
-			if useConcise && !analyzeMode && !generateMode {
-				out, _, success := executor.RunCommandWithRetry(
-					ctx, verCmdArgs, [][]string{{"--version"}, {"-v"}, {"version"}}, program,
-				)
-				if success {
-					verOutput = out
-				} else {
-					verOutput = "无法获取版本信息"
+			case err != nil:
+				fmt.Println(err)
+				return
+			default:
+				helpOutput = probe.help
+				usedCmd = probe.usedCmd
+				verOutput = probe.version
+				// 询问帮助指令时已发现 AI 服务不可用，常规查询直接使用离线速查表
+				if probe.aiErr != nil && mode == ai.ModeLookup && offlineErr == nil {
+					offlineErr = probe.aiErr
 				}
 			}
 		}
@@ -130,8 +192,17 @@ var rootCmd = &cobra.Command{
 		if analyzeMode {
 			// 使用 reconstructArgs 为包含空格的参数添加引号，防止 AI 解析错误
 			fullCommand := reconstructArgs(args)
-			if err := aiClient.ExplainCommand(ctx, useStream, fullCommand, helpOutput, cmdPath); err != nil {
-				fmt.Println("AI 解析失败:", err)
+			spinner := render.StartSpinner(i18n.T("root.spinner_analyze"))
+			answer, err := aiClient.ExplainCommand(ctx, useStream, fullCommand, helpOutput, cmdPath)
+			spinner.Stop()
+			if err != nil {
+				reportAIError(i18n.T("root.err_analyze"), err)
+				return
+			}
+			assessRisk(newRiskEngine(cfg), fullCommand, &answer.Risk)
+			printAnswer(fullCommand, answer)
+			if chatMode {
+				runChat(ctx, aiClient)
 			}
 			return
 		}
@@ -140,18 +211,61 @@ var rootCmd = &cobra.Command{
 		if generateMode {
 			description := subQuery
 			if description == "" {
-				fmt.Println("错误: 生成模式需要提供自然语言描述 (例如: ghp -g git 设置全局用户名)")
+				fmt.Println(i18n.T("root.err_generate_description"))
+				return
+			}
+			spinner := render.StartSpinner(i18n.T("root.spinner_generate"))
+			answer, err := aiClient.GenerateCommand(ctx, useStream, program, description, helpOutput, cmdPath)
+			spinner.Stop()
+			if err != nil {
+				reportAIError(i18n.T("root.err_generate"), err)
 				return
 			}
-			if err := aiClient.GenerateCommand(ctx, useStream, program, description, helpOutput, cmdPath); err != nil {
-				fmt.Println("AI 生成失败:", err)
+			answer = verifyGenerated(ctx, aiClient, program, helpOutput, answer)
+			engine := newRiskEngine(cfg)
+			modelLevel := assessRisk(engine, answer.Command, &answer.Risk)
+			printAnswer(program, answer)
+			exitCode := 0
+			if runMode {
+				exitCode = runGenerated(engine, program, answer.Command, modelLevel)
+			}
+			if chatMode {
+				runChat(ctx, aiClient)
+			}
+			// 以执行的命令的退出状态作为 ghp 的退出状态，便于脚本判断
+			if exitCode != 0 {
+				os.Exit(exitCode)
 			}
 			return
 		}
 
-		// 7. 常规 AI 分析并输出 (支持未安装模式)
-		if err := aiClient.AnalyzeHelpDoc(ctx, useStream, useConcise, isMissing, subQuery, usedCmd, helpOutput, verOutput, cmdPath); err != nil {
-			fmt.Println("AI 分析失败:", err)
+		// 7. 常规 AI 分析并输出 (支持未安装模式)，AI 服务不可用时降级为离线速查表
+		// 本地有 tldr 页面时，其中的示例作为模型生成示例的依据
+		page := findTLDR(cfg, program, subQuery)
+		var answer ai.Answer
+		if offlineErr == nil {
+			spinner := render.StartSpinner(i18n.T("root.spinner_lookup"))
+			answer, err = aiClient.AnalyzeHelpDoc(ctx, useStream, useConcise, isMissing, subQuery, usedCmd, helpOutput, verOutput, cmdPath, tldrExamples(page))
+			spinner.Stop()
+			if ai.Unavailable(err) {
+				offlineErr, err = err, nil
+			}
+			if err != nil {
+				reportAIError(i18n.T("root.err_lookup"), err)
+				return
+			}
+		}
+		if offlineErr != nil {
+			// 未安装的命令没有帮助文档，离线时只能使用 tldr 页面回答
+			if isMissing && page == nil {
+				reportAIError(i18n.T("root.err_lookup"), offlineErr)
+				return
+			}
+			answer = offlineAnswer(offlineErr, subQuery, helpOutput, verOutput, cmdPath, page)
+		}
+		printAnswer(strings.TrimSpace(program+" "+subQuery), answer)
+		if chatMode && offlineErr == nil {
+			runChat(ctx, aiClient)
 		}
 	},
 }
@@ -163,6 +277,125 @@ func Execute() {
 	}
 }
 
+// loadConfig 加载配置并以命令行参数覆盖
+// 优先级: 配置文件 < 项目配置 < 环境变量 < 配置档 (--profile 或该模式的默认配置档) < 命令行参数
+func loadConfig(cmd *cobra.Command, mode string) (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	// 探测设置与 AI 无关，在校验 API Key 之前应用，离线速查同样遵守
+	applyProbeConfig(cfg)
+
+	flags := cmd.Flags()
+	profile := cfg.ProfileFor(mode)
+	if flags.Changed("profile") {
+		profile = profileFlag
+	}
+	if profile != "" {
+		if err := cfg.ApplyProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+	if flags.Changed("provider") {
+		cfg.Provider = providerFlag
+	}
+	if flags.Changed("model") {
+		cfg.Model = modelFlag
+	}
+	if flags.Changed("temperature") {
+		cfg.Temperature = &temperatureFlag
+	}
+	if flags.Changed("lang") {
+		cfg.Language = langFlag
+	}
+	if flags.Changed("concise") {
+		cfg.Concise = &useConcise
+	}
+	if flags.Changed("stream") {
+		cfg.Stream = &useStream
+	}
+	if err := cfg.Finalize(); err != nil {
+		return nil, err
+	}
+
+	useConcise = *cfg.Concise
+	useStream = *cfg.Stream
+	return cfg, nil
+}
+
+// applyProbeConfig 应用沙箱、探测超时和探测命令的允许/禁止列表，未设置的项保持默认值
+func applyProbeConfig(cfg *config.Config) {
+	if cfg.Timeouts.Probe != 0 {
+		executor.ProbeTimeout = cfg.Timeouts.Probe
+	}
+	if cfg.Timeouts.ShellProbe != 0 {
+		executor.ShellProbeTimeout = cfg.Timeouts.ShellProbe
+	}
+	if cfg.Sandbox != nil {
+		executor.Sandbox = *cfg.Sandbox
+	}
+	executor.ProbeAllow = cfg.ProbePolicy.Allow
+	executor.ProbeDeny = cfg.ProbePolicy.Deny
+}
+
+// applyLanguage 设置界面语言
+// 优先级: 环境变量 LANG < 配置文件 / GHP_LANGUAGE < --lang
+// 配置文件有误时不在此报错，由各命令加载配置时统一处理
+func applyLanguage(cmd *cobra.Command) {
+	if cfg, err := config.Load(); err == nil {
+		i18n.SetLanguage(cfg.Language)
+	}
+	if cmd.Flags().Changed("lang") {
+		i18n.SetLanguage(langFlag)
+	}
+}
+
+// newAIClient 根据配置创建 AI 客户端
+func newAIClient(cfg *config.Config) *ai.Client {
+	promptDir, _ := config.PromptDir()
+	return ai.NewClient(newProvider(cfg), ai.Options{
+		Model:       cfg.Model,
+		Temperature: *cfg.Temperature,
+		Timeout:     cfg.Timeouts.Request,
+		Language:    cfg.Language,
+		PromptDir:   promptDir,
+	})
+}
+
+// reportAIError 输出 AI 请求失败的原因，用户主动中断 (Ctrl+C) 时不输出
+func reportAIError(prefix string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	fmt.Println(prefix, err)
+}
+
+// printAnswer 按输出格式打印结构化回答，title 用作文档标题
+func printAnswer(title string, answer ai.Answer) {
+	switch {
+	case outputFormat == outputJSON:
+		data, _ := json.MarshalIndent(answer, "", "  ")
+		fmt.Println(string(data))
+	case docFormat != "":
+		render.WriteDocument(os.Stdout, docFormat, title, answer)
+	default:
+		render.New(os.Stdout).Render(answer)
+	}
+}
+
+// newProvider 根据配置选择大模型后端
+func newProvider(cfg *config.Config) ai.Provider {
+	switch cfg.Provider {
+	case config.ProviderAnthropic:
+		return ai.NewAnthropicProvider(cfg.APIKey, cfg.BaseURL)
+	case config.ProviderOllama:
+		return ai.NewOllamaProvider(cfg.BaseURL)
+	default:
+		return ai.NewOpenAIProvider(cfg.NewClientConfig())
+	}
+}
+
 // reconstructArgs 重组参数，为包含空格的参数添加引号
 // 例如: ["git", "commit", "-m", "fix bug"] -> "git commit -m \"fix bug\""
 func reconstructArgs(args []string) string {
@@ -178,21 +411,55 @@ func reconstructArgs(args []string) string {
 }
 
 func init() {
-	rootCmd.Flags().BoolVarP(&useStream, "stream", "s", true, "是否使用流式输出")
-	rootCmd.Flags().BoolVarP(&useConcise, "concise", "c", true, "是否精简输出")
-	rootCmd.Flags().BoolVarP(&forceMode, "force", "f", false, "强制查询模式 (即使命令不存在也查询)")
-	rootCmd.Flags().BoolVarP(&analyzeMode, "analyze", "a", false, "解析模式 (解释具体命令及参数含义)")
-	rootCmd.Flags().BoolVarP(&generateMode, "generate", "g", false, "生成模式 (根据自然语言描述生成命令)")
+	rootCmd.Flags().BoolVarP(&useStream, "stream", "s", true, i18n.T("flag.stream"))
+	rootCmd.Flags().BoolVarP(&useConcise, "concise", "c", true, i18n.T("flag.concise"))
+	rootCmd.Flags().BoolVarP(&forceMode, "force", "f", false, i18n.T("flag.force"))
+	rootCmd.Flags().BoolVarP(&analyzeMode, "analyze", "a", false, i18n.T("flag.analyze"))
+	rootCmd.Flags().BoolVarP(&generateMode, "generate", "g", false, i18n.T("flag.generate"))
+	rootCmd.Flags().BoolVarP(&chatMode, "chat", "i", false, i18n.T("flag.chat"))
+	rootCmd.Flags().BoolVar(&runMode, "run", false, i18n.T("flag.run"))
+	rootCmd.Flags().StringVarP(&outputFormat, "output", "o", outputText, i18n.T("flag.output"))
+	rootCmd.Flags().StringVar(&docFormat, "format", "", i18n.T("flag.format"))
+	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", i18n.T("flag.lang"))
+	addAIFlags(rootCmd)
 
 	// 关键修复：禁用 Flag 穿插解析
 	// 一旦遇到第一个非 Flag 参数（如 "go"），后续所有内容（包括 -v, --help 等）都将作为 Args 处理
 	// 这对于像 `ghp go build -v` 这样的子命令透传至关重要
 	rootCmd.Flags().SetInterspersed(false)
+
+	// 错误统一由 Execute 输出，避免重复打印
+	rootCmd.SilenceErrors = true
+
+	// 不注册 completion 和 help 子命令，避免占用 `ghp completion`、`ghp help` 之类的查询
+	// (help 是 Shell 内置命令)；各命令的帮助仍可通过 --help 查看
+	// cobra 总会注册一个帮助子命令，这里换成以 - 开头的名称，命令行中无法作为子命令匹配到
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.SetHelpCommand(&cobra.Command{Use: "-help", Hidden: true})
 }
 
+// addAIFlags 注册缓存和大模型后端相关参数，查询和导出共用
+func addAIFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, i18n.T("flag.no_cache"))
+	cmd.Flags().BoolVar(&refreshCache, "refresh", false, i18n.T("flag.refresh"))
+	cmd.Flags().StringVarP(&profileFlag, "profile", "p", "", i18n.T("flag.profile"))
+	cmd.Flags().StringVar(&providerFlag, "provider", "", i18n.T("flag.provider"))
+	cmd.Flags().StringVar(&modelFlag, "model", "", i18n.T("flag.model"))
+	cmd.Flags().Float32Var(&temperatureFlag, "temperature", 1, i18n.T("flag.temperature"))
+}
+
+// interruptAnswer 追问模式正在生成回答时的取消函数，此时 Ctrl+C 只中断这一次回答
+var interruptAnswer atomic.Pointer[context.CancelFunc]
+
 func gracefulShutdown(cancel context.CancelFunc) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	cancel()
+	for sig := range quit {
+		if stop := interruptAnswer.Load(); sig == syscall.SIGINT && stop != nil {
+			(*stop)()
+			continue
+		}
+		cancel()
+		return
+	}
 }
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/executor"
+)
+
+// 未设置 API Key 时查询降级为离线速查，探测设置仍需生效
+func TestLoadConfigOfflineAppliesProbeConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	for _, key := range []string{"GHP_PROVIDER", "GHP_API_KEY", "GHP_BASE_URL", "GHP_MODEL", "GHP_PROFILE"} {
+		t.Setenv(key, "")
+	}
+	t.Chdir(t.TempDir()) // 避免读到仓库中的项目配置
+
+	path := filepath.Join(home, "ghp", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := "sandbox: false\ntimeouts:\n  probe: 5s\nprobe_policy:\n  allow: [\"mytool manual\"]\n  deny: [\"foo *\"]\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sandbox, timeout, shellTimeout := executor.Sandbox, executor.ProbeTimeout, executor.ShellProbeTimeout
+	t.Cleanup(func() {
+		executor.Sandbox, executor.ProbeTimeout, executor.ShellProbeTimeout = sandbox, timeout, shellTimeout
+		executor.ProbeAllow, executor.ProbeDeny = nil, nil
+	})
+
+	if _, err := loadConfig(rootCmd, ai.ModeLookup); err == nil {
+		t.Fatal("loadConfig() 未设置 API Key 时应返回错误")
+	}
+	if executor.Sandbox {
+		t.Error("executor.Sandbox = true, 配置中已关闭沙箱")
+	}
+	if executor.ProbeTimeout != 5*time.Second {
+		t.Errorf("executor.ProbeTimeout = %v, want 5s", executor.ProbeTimeout)
+	}
+	if executor.ShellProbeTimeout != shellTimeout {
+		t.Errorf("executor.ShellProbeTimeout = %v, 未设置时应保持默认值 %v", executor.ShellProbeTimeout, shellTimeout)
+	}
+	if !slices.Equal(executor.ProbeAllow, []string{"mytool manual"}) {
+		t.Errorf("executor.ProbeAllow = %q", executor.ProbeAllow)
+	}
+	if !slices.Equal(executor.ProbeDeny, []string{"foo *"}) {
+		t.Errorf("executor.ProbeDeny = %q", executor.ProbeDeny)
+	}
+	if err := executor.CheckProbe("foo", "/usr/bin/foo", []string{"foo", "--help"}); err == nil {
+		t.Error(`CheckProbe("foo --help") 应被禁止列表拦截`)
+	}
+}
+
+// 优先级: 环境变量 < 配置档 < 命令行参数
+func TestLoadConfigPrecedence(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GHP_PROVIDER", "")
+	t.Setenv("GHP_BASE_URL", "")
+	t.Setenv("GHP_API_KEY", "env-key")
+	t.Setenv("GHP_MODEL", "env-model")
+	t.Chdir(t.TempDir())
+	path := filepath.Join(home, "ghp", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("model: user-model\nprofiles:\n  p:\n    model: profile-model\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	model := modelFlag
+	t.Cleanup(func() {
+		modelFlag = model
+		executor.ProbeAllow, executor.ProbeDeny = nil, nil
+	})
+
+	tests := []struct {
+		name    string
+		profile string
+		flag    string
+		want    string
+	}{
+		{name: "环境变量", want: "env-model"},
+		{name: "配置档覆盖环境变量", profile: "p", want: "profile-model"},
+		{name: "命令行参数覆盖配置档", profile: "p", flag: "flag-model", want: "flag-model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GHP_PROFILE", tt.profile)
+			cmd := &cobra.Command{}
+			addAIFlags(cmd)
+			if tt.flag != "" {
+				cmd.Flags().Set("model", tt.flag)
+			}
+			cfg, err := loadConfig(cmd, ai.ModeLookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Model != tt.want {
+				t.Errorf("Model = %q, want %q", cfg.Model, tt.want)
+			}
+		})
+	}
+}
+
+// `ghp help` 查询 Shell 内置命令 help，不能被 cobra 的帮助子命令占用
+func TestHelpIsLookedUp(t *testing.T) {
+	rootCmd.InitDefaultHelpCmd() // Execute 时才会注册帮助子命令
+	for _, args := range [][]string{{"help"}, {"help", "cd"}, {"-c=false", "help"}} {
+		cmd, _, err := rootCmd.Find(args)
+		if err != nil || cmd != rootCmd {
+			t.Errorf("Find(%q) = %s, %v, want ghp", args, cmd.Name(), err)
+		}
+	}
+	if cmd, _, err := rootCmd.Find([]string{"cache", "list"}); err != nil || cmd != cacheListCmd {
+		t.Errorf(`Find("cache list") = %s, %v, want list`, cmd.Name(), err)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+
+	"ghp/pkg/executor"
+	"ghp/pkg/i18n"
+	"ghp/pkg/render"
+	"ghp/pkg/risk"
+)
+
+// 没有执行命令时 ghp 的退出状态码，沿用 Shell 的约定，脚本可以与命令自身的退出状态区分
+const (
+	exitNotRun    = 1   // 无法执行：标准输入不是终端或命令无法启动
+	exitCancelled = 130 // 用户取消，与 Ctrl+C 中断相同 (128 + SIGINT)
+)
+
+// runGenerated 确认后在用户的 Shell 中执行生成的命令，返回退出状态码
+// 执行前可以在编辑器中修改命令；取消时返回 exitCancelled，无法执行时返回 exitNotRun
+// 每次确认前都用本地规则重新评估命令 (不低于模型给出的 modelLevel)，破坏性的命令必须完整输入 yes 才会执行
+func runGenerated(engine *risk.Engine, program, command, modelLevel string) int {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println(i18n.T("run.err_no_tty"))
+		return exitNotRun
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	for {
+		command = strings.TrimSpace(command)
+		if command == "" {
+			fmt.Println(i18n.T("run.cancelled"))
+			return exitCancelled
+		}
+		fmt.Println()
+		fmt.Println(i18n.T("run.command"))
+		render.New(os.Stdout).CommandLine(command)
+		// 破坏性的命令必须完整输入 yes，避免习惯性地按 y 确认
+		level := risk.Max(modelLevel, risk.Level(engine.Check(command)))
+		dangerous := risk.Rank(level) >= risk.Rank(risk.Destructive)
+		question := i18n.T("run.confirm")
+		if dangerous {
+			question = i18n.T("run.confirm_dangerous", i18n.T("risk.level."+level))
+		}
+		switch reply := strings.ToLower(prompt(in, question, "")); {
+		case reply == "yes" || (reply == "y" && !dangerous):
+			return execute(program, command)
+		case reply == "e" || reply == "edit":
+			edited, err := editCommand(command)
+			if err != nil {
+				fmt.Println(i18n.T("run.err_edit", err))
+				continue
+			}
+			command = edited
+		default:
+			fmt.Println(i18n.T("run.cancelled"))
+			return exitCancelled
+		}
+	}
+}
+
+// execute 执行命令并输出退出状态
+// 主命令不是可执行文件时 (如 nvm 等 Shell 函数) 使用交互式 Shell 执行
+func execute(program, command string) int {
+	_, lookErr := exec.LookPath(program)
+	code, err := executor.RunInShell(command, lookErr != nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("run.err_start", err))
+		return exitNotRun
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("run.exit_status", code))
+	return code
+}
+
+// editCommand 在编辑器中修改命令，以 # 开头的行为注释
+func editCommand(command string) (string, error) {
+	f, err := os.CreateTemp("", "ghp-run-*.sh")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	_, err = fmt.Fprintf(f, "%s\n\n# %s\n", command, i18n.T("run.edit_hint"))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := executor.EditFile(f.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
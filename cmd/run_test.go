@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ghp/pkg/risk"
+)
+
+// 标准输入不是终端时无法确认，不执行命令并以 1 退出，而不是 -1 (即 255)
+func TestRunGeneratedWithoutTTY(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	w.WriteString("yes\n")
+	w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() { os.Stdin = stdin })
+
+	engine, err := risk.NewEngine(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "created")
+	if code := runGenerated(engine, "touch", "touch "+path, risk.Safe); code != exitNotRun {
+		t.Errorf("runGenerated() = %d, want %d", code, exitNotRun)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("标准输入不是终端时不应执行命令")
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/config"
+	"ghp/pkg/i18n"
+	"ghp/pkg/tldr"
+)
+
+// findTLDR 查找程序 (或 "程序 子命令") 对应的本地 tldr 页面，没有时返回 nil
+// 子查询是单个子命令时查找 git-commit 这样的子命令页面，其他子查询 (参数、自然语言) 不使用 tldr
+// 配置不可用 (如未设置 API Key，cfg 为 nil) 时按界面语言查找，tldr_dir 直接从配置文件读取
+func findTLDR(cfg *config.Config, program, subQuery string) *tldr.Page {
+	name := filepath.Base(program)
+	if subQuery != "" {
+		if strings.ContainsAny(subQuery, " \t") || strings.HasPrefix(subQuery, "-") {
+			return nil
+		}
+		name += "-" + subQuery
+	}
+	language, dir := i18n.Language(), ""
+	if cfg != nil {
+		language, dir = cfg.Language, cfg.TLDRDir
+	} else if c, err := config.Load(); err == nil {
+		dir = c.TLDRDir
+	}
+	return tldr.Find(name, language, dir)
+}
+
+// tldrExamples 将 tldr 页面中的示例转为回答中的示例
+func tldrExamples(page *tldr.Page) []ai.Example {
+	if page == nil {
+		return nil
+	}
+	examples := make([]ai.Example, len(page.Examples))
+	for i, e := range page.Examples {
+		examples[i] = ai.Example{Command: e.Command, Description: e.Description, TLDR: true}
+	}
+	return examples
+}
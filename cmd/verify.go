@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/i18n"
+	"ghp/pkg/render"
+	"ghp/pkg/verify"
+)
+
+// verifyGenerated 将生成的命令与帮助文档比对，发现帮助文档中不存在的选项或子命令时请模型修正一次
+// 比对结果记录在返回值的 Verification 中；没有帮助文档 (如未安装的命令) 时不做校验
+// 帮助文档只列出常用子命令时，未列出的子命令只记为无法校验，不请模型修正
+func verifyGenerated(ctx context.Context, aiClient *ai.Client, program, helpOutput string, answer *ai.GeneratedCommand) *ai.GeneratedCommand {
+	if strings.TrimSpace(helpOutput) == "" {
+		return answer
+	}
+	inv := verify.NewInventory(helpOutput)
+	unknown, unverified := inv.Check(program, answer.Command)
+	if len(unknown) == 0 {
+		answer.Verification = &ai.Verification{Unverified: unverified}
+		return answer
+	}
+
+	spinner := render.StartSpinner(i18n.T("verify.spinner"))
+	repaired, err := aiClient.RepairCommand(ctx, useStream, program, unknown)
+	spinner.Stop()
+	if err != nil {
+		answer.Verification = &ai.Verification{Flagged: unknown, Unknown: unknown, Unverified: unverified}
+		return answer
+	}
+	remaining, unverified := inv.Check(program, repaired.Command)
+	repaired.Verification = &ai.Verification{
+		Repaired:   repaired.Command != answer.Command,
+		Original:   answer.Command,
+		Flagged:    unknown,
+		Unknown:    remaining,
+		Unverified: unverified,
+	}
+	if !repaired.Verification.Repaired {
+		repaired.Verification.Original = ""
+	}
+	return repaired
+}
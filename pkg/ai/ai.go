@@ -5,62 +5,109 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
-	"github.com/sashabaranov/go-openai"
+	"ghp/pkg/cache"
+	"ghp/pkg/i18n"
 )
 
+// Options 客户端参数
+type Options struct {
+	Model       string
+	Temperature float32
+	Timeout     time.Duration // 单次请求超时，0 表示不限制
+	Language    string        // 回答使用的语言，如 zh、en，为空时使用中文
+	PromptDir   string        // 用户自定义提示词模板目录，其中的同名模板优先于内置模板
+}
+
 type Client struct {
-	client *openai.Client
-	model  string
+	provider Provider
+	opts     Options
+	answers  *answerCache
+	last     []Message // 最近一次回答的完整对话，用于追问
 }
 
-func NewClient(cfg openai.ClientConfig, model string) *Client {
+// NewClient 基于指定的大模型后端创建客户端
+func NewClient(provider Provider, opts Options) *Client {
 	return &Client{
-		client: openai.NewClientWithConfig(cfg),
-		model:  model,
+		provider: provider,
+		opts:     opts,
 	}
 }
 
+// withTimeout 为单次请求附加超时
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.opts.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.opts.Timeout)
+}
+
+// language 回答语言在提示词中的名称
+func (c *Client) language() string {
+	return i18n.Name(c.opts.Language)
+}
+
+// t 返回回答语言的提示词文案，与界面语言无关
+func (c *Client) t(key string, args ...any) string {
+	return i18n.TFor(c.opts.Language, key, args...)
+}
+
+// probeCommands 持久化的帮助/版本查询命令
+type probeCommands struct {
+	Program string   `json:"program"`
+	Help    []string `json:"help"`
+	Version []string `json:"version"`
+}
+
 // GetHelpCommand 获取帮助和版本查询命令
+// 依次查询内置常见工具表、本地缓存，都未命中时才询问 AI，并将结果按 程序+系统 缓存
 // 返回：(帮助命令, 版本命令, 错误)
 func (c *Client) GetHelpCommand(ctx context.Context, program string) ([]string, []string, error) {
 	osname := runtime.GOOS
-	resp, err := c.client.CreateChatCompletion(
+	if helpCmd, verCmd, ok := lookupKnownHelpCommand(osname, program); ok {
+		return helpCmd, verCmd, nil
+	}
+
+	key := osname + "/" + program
+	store, storeErr := cache.Open(cache.NamespaceCommands)
+	if storeErr == nil {
+		var cached probeCommands
+		hit, _ := store.Get(key, &cached)
+		store.RecordLookup(hit)
+		if hit {
+			return cached.Help, cached.Version, nil
+		}
+	}
+
+	systemPrompt, err := c.prompt(PromptHelpCommand)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	content, err := c.provider.Complete(
 		ctx,
-		openai.ChatCompletionRequest{
-			Model: c.model,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role: openai.ChatMessageRoleSystem,
-					Content: "你是一个命令行专家。请直接给出获取以下程序信息的**最佳命令**。\n\n" +
-						"规则：\n" +
-						"1. **输出两行**：\n" +
-						"   - 第一行：获取帮助信息的命令 (如 git --help)\n" +
-						"   - 第二行：获取版本信息的命令 (如 git --version)。如果该程序没有版本命令，第二行输出 `NONE`。\n" +
-						"2. **只输出命令**：不要包含任何解释、Markdown 格式。每行只包含一个可执行命令。\n" +
-						"3. **优先标准参数**：优先使用 `--help` 和 `--version`。\n" +
-						"4. **内置命令处理**：Shell 内置命令使用 `help` 或 `man`，版本命令输出 `NONE`。\n" +
-						"5. **示例**：\n" +
-						"   输入: git\n" +
-						"   输出:\n" +
-						"   git --help\n" +
-						"   git --version",
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: fmt.Sprintf("我的系统是%s, 我需要查询的命令是: %s", osname, program),
-				},
+		Request{
+			Model: c.opts.Model,
+			Messages: []Message{
+				{Role: RoleSystem, Content: systemPrompt},
+				{Role: RoleUser, Content: c.t("prompt.help_command", osname, program)},
 			},
-			Temperature: 1,
+			Temperature: c.opts.Temperature,
 		},
 	)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	lines := strings.Split(strings.TrimSpace(resp.Choices[0].Message.Content), "\n")
+	lines := strings.Split(strings.TrimSpace(content), "\n")
 	var helpCmd, verCmd []string
 	if len(lines) > 0 && strings.TrimSpace(lines[0]) != "" {
 		helpCmd = strings.Fields(strings.TrimSpace(lines[0]))
@@ -68,315 +115,281 @@ func (c *Client) GetHelpCommand(ctx context.Context, program string) ([]string,
 	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "NONE" {
 		verCmd = strings.Fields(strings.TrimSpace(lines[1]))
 	}
+	if storeErr == nil && len(helpCmd) > 0 {
+		store.Put(key, probeCommands{Program: program, Help: helpCmd, Version: verCmd})
+	}
 	return helpCmd, verCmd, nil
 }
 
-// AnalyzeHelpDoc 分析帮助文档并输出
-// 支持流式输出，支持精简/普通模式，支持强制查询（未安装）模式
-func (c *Client) AnalyzeHelpDoc(ctx context.Context, useStream, useConcise, isMissing bool, subQuery, usedCmd, helpOutput, versionOutput, cmdPath string) error {
-	osname := runtime.GOOS
-	systemPrompt := c.buildSystemPrompt(useConcise, isMissing, subQuery)
-	userContent := c.buildUserPrompt(osname, usedCmd, helpOutput, versionOutput, subQuery, cmdPath, isMissing, useConcise)
+// chatRequest 一轮 system + user 对话
+type chatRequest struct {
+	mode         string
+	program      string
+	systemPrompt string
+	userContent  string
+	helpDoc      string
+	answer       Answer // 用于解码回答的结构体指针
+}
 
-	req := openai.ChatCompletionRequest{
-		Model: c.model,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
-			{Role: openai.ChatMessageRoleUser, Content: userContent},
-		},
-		Temperature: 1,
+// chat 按 JSON Schema 请求结构化回答并解码到 cr.answer
+// useStream 为 true 时使用流式接口接收，避免长回答被代理或网关的空闲超时中断；
+// 结构化回答要完整接收后才能解码排版，因此流式只影响网络传输，不会逐段输出
+// 开启回答缓存时优先使用缓存的回答
+func (c *Client) chat(ctx context.Context, useStream bool, cr chatRequest) error {
+	schema := schemaFor(cr.answer)
+	// 并非所有后端都支持原生的 JSON Schema 约束，因此同时在提示词中说明
+	cr.systemPrompt += "\n\n" + c.t("prompt.schema", schema.Schema)
+	messages := []Message{
+		{Role: RoleSystem, Content: cr.systemPrompt},
+		{Role: RoleUser, Content: cr.userContent},
 	}
+	return c.send(ctx, useStream, cr, messages, schema)
+}
 
-	if useStream {
-		stream, err := c.client.CreateChatCompletionStream(ctx, req)
-		if err != nil {
-			return err
-		}
-		defer stream.Close()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return nil
-			default:
-			}
-			resp, err := stream.Recv()
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			if err != nil {
-				return err
-			}
-			fmt.Print(resp.Choices[0].Delta.Content)
-		}
-		fmt.Println()
+// send 发送对话并解码结构化回答，回答按完整的对话内容缓存
+// 命中缓存的回答与新回答一样解码后交给调用方排版，输出完全一致
+func (c *Client) send(ctx context.Context, useStream bool, cr chatRequest, messages []Message, schema *Schema) error {
+	contents := make([]string, len(messages))
+	for i, m := range messages {
+		contents[i] = m.Content
+	}
+	key := answerKey(cr.mode, c.opts.Model, strings.Join(contents, "\n"), cr.helpDoc)
+	if answer, ok := c.answers.get(key); ok && decodeAnswer(answer, cr.answer) == nil {
+		c.last = append(messages, Message{Role: RoleAssistant, Content: answer})
 		return nil
 	}
 
-	resp, err := c.client.CreateChatCompletion(ctx, req)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	req := Request{
+		Model:       c.opts.Model,
+		Messages:    messages,
+		Temperature: c.opts.Temperature,
+		Schema:      schema,
+	}
+
+	var content string
+	var err error
+	if useStream {
+		content, err = c.receiveStream(ctx, req, nil)
+	} else {
+		content, err = c.provider.Complete(ctx, req)
+	}
 	if err != nil {
 		return err
 	}
-	fmt.Println(resp.Choices[0].Message.Content)
+	if err := decodeAnswer(content, cr.answer); err != nil {
+		return err
+	}
+	c.last = append(messages, Message{Role: RoleAssistant, Content: content})
+	c.answers.put(key, answerEntry{Mode: cr.mode, Model: c.opts.Model, Program: cr.program, Answer: content})
 	return nil
 }
 
-// ExplainCommand 解析并解释完整的命令 (-a 模式)
-// 侧重于拆解参数含义和提供优化建议
-func (c *Client) ExplainCommand(ctx context.Context, useStream bool, fullCommand, helpOutput, cmdPath string) error {
-	osname := runtime.GOOS
-	systemPrompt := "你是一个命令行专家。用户输入了一条具体的命令，你需要详细解析该命令的含义，并给出优化建议。\n\n" +
-		"【必须遵守的规则】\n" +
-		"1. **格式统一**：请严格遵守下方的【输出格式范例】，保持版面整洁。\n" +
-		"2. **逐层解析**：拆解命令的每一个部分（主命令、子命令、Flag参数、参数值），解释其具体作用。\n" +
-		"3. **总结作用**：用一句话概括这条命令执行后会发生什么。\n" +
-		"4. **优化建议**：基于该命令的意图，给出 1-2 条优化建议、更现代的替代方案，或者执行该命令后的常见后续操作。\n" +
-		"5. **严禁 Markdown**：绝对不要使用 markdown 格式。输出必须是纯文本，使用缩进和列表来组织结构。\n" +
-		"6. **准确性**：必须参考提供的帮助文档，不要编造参数含义。\n\n" +
-		"【输出格式范例】\n" +
-		"命令: git commit -am 'fix bug'\n" +
-		"位置: /usr/bin/git\n\n" +
-		"解析:\n" +
-		"  git commit   提交代码更改\n" +
-		"  -a           自动暂存所有已修改的文件（不包括新文件）\n" +
-		"  -m '...'     指定提交说明信息\n\n" +
-		"总结: 暂存所有已跟踪文件的修改并创建新的提交。\n\n" +
-		"建议:\n" +
-		"  - 如果有未跟踪的新文件，请先执行 `git add .`\n" +
-		"  - 提交后通常需要执行 `git push` 推送到远程仓库"
-
-	userContent := fmt.Sprintf("我的系统环境是%s\n命令安装位置: %s\n\n**用户输入的完整命令**: %s\n\n参考帮助文档:\n%s", osname, cmdPath, fullCommand, helpOutput)
-
-	req := openai.ChatCompletionRequest{
-		Model: c.model,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
-			{Role: openai.ChatMessageRoleUser, Content: userContent},
-		},
-		Temperature: 1,
+// receiveStream 通过流式接口接收完整回答，onDelta 不为空时逐段回调
+func (c *Client) receiveStream(ctx context.Context, req Request, onDelta func(string)) (string, error) {
+	stream, err := c.provider.Stream(ctx, req)
+	if err != nil {
+		return "", err
 	}
+	defer stream.Close()
 
-	if useStream {
-		stream, err := c.client.CreateChatCompletionStream(ctx, req)
+	var sb strings.Builder
+	for {
+		delta, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return sb.String(), nil
+		}
 		if err != nil {
-			return err
+			return "", err
 		}
-		defer stream.Close()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return nil
-			default:
-			}
-			resp, err := stream.Recv()
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			if err != nil {
-				return err
-			}
-			fmt.Print(resp.Choices[0].Delta.Content)
+		sb.WriteString(delta)
+		if onDelta != nil {
+			onDelta(delta)
 		}
-		fmt.Println()
-		return nil
 	}
+}
 
-	resp, err := c.client.CreateChatCompletion(ctx, req)
+// AnalyzeHelpDoc 分析帮助文档，返回速查表 (*CheatSheet) 或子命令卡片 (*SubcommandCard)
+// 支持精简/普通模式，支持强制查询（未安装）模式
+// vetted 为本地 tldr 页面中经过审核的示例，作为模型生成示例的依据，可以为空
+func (c *Client) AnalyzeHelpDoc(ctx context.Context, useStream, useConcise, isMissing bool, subQuery, usedCmd, helpOutput, versionOutput, cmdPath string, vetted []Example) (Answer, error) {
+	osname := runtime.GOOS
+	systemPrompt, err := c.prompt(lookupPrompt(useConcise, isMissing, subQuery))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	fmt.Println(resp.Choices[0].Message.Content)
-	return nil
+	userContent := c.buildUserPrompt(osname, usedCmd, helpOutput, versionOutput, subQuery, cmdPath, isMissing, useConcise)
+	userContent += c.vettedPrompt(vetted)
+
+	var answer Answer = &CheatSheet{}
+	if subQuery != "" {
+		answer = &SubcommandCard{}
+	}
+	err = c.chat(ctx, useStream, chatRequest{
+		mode:         ModeLookup,
+		program:      programName(usedCmd),
+		systemPrompt: systemPrompt,
+		userContent:  userContent,
+		helpDoc:      helpOutput + "\n" + versionOutput,
+		answer:       answer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch a := answer.(type) {
+	case *CheatSheet:
+		markVetted(a.Examples, vetted)
+	case *SubcommandCard:
+		markVetted(a.Examples, vetted)
+	}
+	return answer, nil
+}
+
+// vettedPrompt 将 tldr 示例附加到用户提示中
+func (c *Client) vettedPrompt(vetted []Example) string {
+	if len(vetted) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n" + c.t("prompt.vetted") + "\n")
+	for _, e := range vetted {
+		fmt.Fprintf(&sb, "- %s: %s\n", e.Description, e.Command)
+	}
+	sb.WriteString(c.t("prompt.vetted_rule"))
+	return sb.String()
+}
+
+// markVetted 标记与 tldr 示例命令一致的示例，比较时忽略占位符名称和多余空白
+func markVetted(examples, vetted []Example) {
+	known := make(map[string]bool, len(vetted))
+	for _, e := range vetted {
+		known[normalizeExample(e.Command)] = true
+	}
+	for i := range examples {
+		examples[i].TLDR = known[normalizeExample(examples[i].Command)]
+	}
+}
+
+var examplePlaceholder = regexp.MustCompile(`<[^<>]*>|\{\{.*?\}\}`)
+
+// normalizeExample 将占位符统一为 <>，合并空白
+func normalizeExample(command string) string {
+	return strings.Join(strings.Fields(examplePlaceholder.ReplaceAllString(command, "<>")), " ")
+}
+
+// ExplainCommand 解析并解释完整的命令 (-a 模式)
+// 侧重于拆解参数含义和提供优化建议
+func (c *Client) ExplainCommand(ctx context.Context, useStream bool, fullCommand, helpOutput, cmdPath string) (*CommandExplanation, error) {
+	osname := runtime.GOOS
+	systemPrompt, err := c.prompt(PromptAnalyze)
+	if err != nil {
+		return nil, err
+	}
+
+	userContent := c.t("prompt.explain", osname, cmdPath, fullCommand, helpOutput)
+
+	answer := &CommandExplanation{}
+	err = c.chat(ctx, useStream, chatRequest{
+		mode:         ModeAnalyze,
+		program:      programName(fullCommand),
+		systemPrompt: systemPrompt,
+		userContent:  userContent,
+		helpDoc:      helpOutput,
+		answer:       answer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
 }
 
 // GenerateCommand 根据自然语言描述生成命令 (-g 模式)
 // 侧重于将自然语言转为准确的 CLI 命令
-func (c *Client) GenerateCommand(ctx context.Context, useStream bool, program, description, helpOutput, cmdPath string) error {
+func (c *Client) GenerateCommand(ctx context.Context, useStream bool, program, description, helpOutput, cmdPath string) (*GeneratedCommand, error) {
 	osname := runtime.GOOS
-	systemPrompt := "你是一个命令行专家。用户指定了一个主命令和一段自然语言描述，请根据帮助文档，将用户的自然语言需求转换为最准确的执行命令。\n\n" +
-		"【必须遵守的规则】\n" +
-		"1. **格式统一**：请严格遵守下方的【输出格式范例】，保持版面整洁。\n" +
-		"2. **生成命令**：直接给出一条可执行的、最符合用户需求的完整命令。\n" +
-		"3. **命令解析**：简要解释命令中用到的关键参数。\n" +
-		"4. **使用简短命令**：除非必要，否则**不要**在生成的命令中使用绝对路径（例如，使用 `git` 而不是 `/usr/bin/git`）。\n" +
-		"5. **相关建议**：执行该命令后的注意事项或下一步操作建议。\n" +
-		"6. **严禁 Markdown**：绝对不要使用 markdown 格式。输出必须是纯文本。\n" +
-		"7. **准确性**：必须参考提供的帮助文档。\n\n" +
-		"【输出格式范例】\n" +
-		"需求: git 设置全局用户名\n" +
-		"位置: /usr/bin/git\n\n" +
-		"推荐命令:\n" +
-		"  git config --global user.name \"Your Name\"\n\n" +
-		"解析:\n" +
-		"  config    修改配置文件\n" +
-		"  --global  写入全局配置 (~/.gitconfig)\n" +
-		"  user.name 设置用户名字段\n\n" +
-		"建议:\n" +
-		"  - 你可能还需要设置邮箱: git config --global user.email \"you@example.com\"\n" +
-		"  - 查看当前配置: git config --list"
-
-	userContent := fmt.Sprintf("我的系统环境是%s\n命令安装位置: %s\n主命令: %s\n**用户需求**: %s\n\n参考帮助文档:\n%s", osname, cmdPath, program, description, helpOutput)
-
-	req := openai.ChatCompletionRequest{
-		Model: c.model,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
-			{Role: openai.ChatMessageRoleUser, Content: userContent},
-		},
-		Temperature: 1,
+	systemPrompt, err := c.prompt(PromptGenerate)
+	if err != nil {
+		return nil, err
 	}
 
-	if useStream {
-		stream, err := c.client.CreateChatCompletionStream(ctx, req)
-		if err != nil {
-			return err
-		}
-		defer stream.Close()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return nil
-			default:
-			}
-			resp, err := stream.Recv()
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			if err != nil {
-				return err
-			}
-			fmt.Print(resp.Choices[0].Delta.Content)
-		}
-		fmt.Println()
-		return nil
+	userContent := c.t("prompt.generate", osname, cmdPath, program, description, helpOutput)
+
+	answer := &GeneratedCommand{}
+	err = c.chat(ctx, useStream, chatRequest{
+		mode:         ModeGenerate,
+		program:      program,
+		systemPrompt: systemPrompt,
+		userContent:  userContent,
+		helpDoc:      helpOutput,
+		answer:       answer,
+	})
+	if err != nil {
+		return nil, err
 	}
+	return answer, nil
+}
 
-	resp, err := c.client.CreateChatCompletion(ctx, req)
+// RepairCommand 生成的命令中存在帮助文档里找不到的选项或子命令时，在同一轮对话中请模型修正
+func (c *Client) RepairCommand(ctx context.Context, useStream bool, program string, unknown []string) (*GeneratedCommand, error) {
+	if len(c.last) == 0 {
+		return nil, errors.New(i18n.T("ai.err_no_conversation"))
+	}
+	userContent := c.t("prompt.repair", strings.Join(unknown, ", "))
+	messages := append(slices.Clone(c.last), Message{Role: RoleUser, Content: userContent})
+
+	answer := &GeneratedCommand{}
+	err := c.send(ctx, useStream, chatRequest{
+		mode:    ModeGenerate,
+		program: program,
+		answer:  answer,
+	}, messages, schemaFor(answer))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	fmt.Println(resp.Choices[0].Message.Content)
-	return nil
+	return answer, nil
 }
 
-func (c *Client) buildSystemPrompt(useConcise, isMissing bool, subQuery string) string {
-	// 场景 1: 未安装模式
-	if isMissing {
-		return "你是一个精通服务器的专家。用户询问的命令**在本地尚未安装**。\n" +
-			"你的任务是根据你的知识库，为用户提供该命令的介绍、安装指南和基础用法。\n\n" +
-			"【必须遵守的规则】\n" +
-			"1. **格式统一**：请严格遵守下方的【输出格式范例】，保持版面整洁。\n" +
-			"2. **介绍**：一句话简要说明该命令的核心功能。\n" +
-			"3. **位置**：必须输出一行 `位置: 该命令尚未安装`。\n" +
-			"4. **安装指南**：结合用户的操作系统，提供 2-3 种推荐的安装方式（按推荐程度排序）。例如 macOS 首选 brew，Linux 首选 apt/yum，Windows 首选 winget/choco。必须包含具体的可执行命令。\n" +
-			"5. **常用示例**：提供 3-5 个最经典的基础用法示例。\n" +
-			"6. **全程中文**：解释说明必须是中文。\n" +
-			"7. **严禁 Markdown**：绝对不要使用 markdown 格式。输出必须是纯文本。\n\n" +
-			"【输出格式范例】\n" +
-			"介绍: 强大的HTTP命令行客户端\n" +
-			"位置: 该命令尚未安装\n\n" +
-			"推荐安装:\n" +
-			"  1. Homebrew (推荐): brew install curl\n" +
-			"  2. 源码编译: wget https://curl.se/download/curl-7.79.1.tar.gz ...\n\n" +
-			"常用示例:\n" +
-			"  curl google.com     # 发送 GET 请求"
-	}
-
-	// 场景 2: 子命令查询模式 (例如 ghp git commit)
-	if subQuery != "" {
-		return "你是一个命令行专家。用户想查询主命令下某个**特定子命令或参数**的具体用法。\n" +
-			"请基于主命令的帮助文档（以及你的专业知识），重点解释该子命令。\n\n" +
-			"【必须遵守的规则】\n" +
-			"1. **意图识别**：首先判断用户输入的子命令/参数内容。如果它是一句**自然语言描述**（例如“如何提交代码”、“重命名分支”、“将文件转为gif”），而非具体的命令参数（如 `commit`, `build -o`, `--help`），请直接输出且仅输出以下提示信息，不要输出其他任何内容：\n" +
-			"   提示: 检测到自然语言描述。如需生成命令，请使用 -g 或 --generate 参数。\n" +
-			"   例如: ghp -g [主命令] [描述]\n" +
-			"2. **验证有效性**：如果不是自然语言，判断其是否为有效的子命令/参数。如果无效，输出“用法错误”。\n" +
-			"3. **格式统一**：如果是有效子命令，请严格遵守下方的【输出格式范例】，保持版面整洁。\n" +
-			"4. **核心解释**：一句话概括该子命令的核心作用（中文）。\n" +
-			"5. **实战示例**：这是重点！提供 3-5 个结合开发经验的、最实用的场景示例（例如 `go build -o app main.go`）。示例必须准确、可执行。\n" +
-			"6. **全程中文**：解释说明必须是中文。\n" +
-			"7. **严禁 Markdown**：绝对不要使用 markdown 格式。输出必须是纯文本，类似精简速查表。\n\n" +
-			"【输出格式范例】\n" +
-			"子命令: build\n" +
-			"作用: 编译包和依赖项\n\n" +
-			"常用用法:\n" +
-			"  go build -o myapp .   # 编译当前目录并指定输出文件名\n" +
-			"  go build -v ./...     # 编译当前及子目录下所有包，并显示进度"
-	}
-
-	// 场景 3: 精简模式 (默认)
-	if useConcise {
-		return "你是一个精通服务器的专家。请为用户生成一份**中文精简速查表**。\n\n" +
-			"【必须遵守的规则】\n" +
-			"1. **格式统一**：请严格遵守下方的【输出格式范例】，保持版面整洁。\n" +
-			"2. **简要介绍**：在输出的第一行，必须先用一句话简要说明该命令的核心功能。\n" +
-			"3. **位置信息**：在介绍下方单列一行 `位置: [程序路径]`（路径由用户提供）。\n" +
-			"4. **版本分析**：从提供的版本信息中提取版本号。如果提供了版本信息，在位置下方单列一行 `版本: x.y.z`。如果未提供，则不显示。\n" +
-			"5. **只看核心**：忽略版本号、版权、页脚等无关信息，只筛选出最常用、最高频的 5-10 个选项/参数。\n" +
-			"6. **全程中文**：所有解释必须是中文。如果原输出是英文，必须翻译。\n" +
-			"7. **严禁 Markdown**：绝对不要使用 markdown 格式。输出必须是纯文本。\n" +
-			"8. **极简风格**：采用紧凑的列表格式。参数解释不超过 20 个字。\n" +
-			"9. **实战示例**：必须提供 3-5 个最经典的实战场景命令。\n" +
-			"10. **无废话**：直接输出内容。\n\n" +
-			"【输出格式范例】\n" +
-			"介绍: 用于列出目录内容及文件信息的常用工具。\n" +
-			"位置: /bin/ls\n" +
-			"版本: 8.32 (如无则省略)\n\n" +
-			"常用选项:\n" +
-			"  -a, --all   显示所有文件（包括隐藏文件）\n" +
-			"  -l          使用详细列表格式\n" +
-			"  -h          以人类可读的格式显示大小\n\n" +
-			"常用示例:\n" +
-			"  ls -lah     # 以列表方式显示所有文件的大小\n" +
-			"  ls *.go     # 列出所有 go 文件"
-	}
-
-	// 场景 4: 完整模式 (非精简)
-	return "你是一个精通服务器及各种编程语言的专家。用户会提供一段程序命令的帮助文档。\n" +
-		"你的任务是生成一份高质量的**中文帮助手册**。\n\n" +
-		"【必须遵守的规则】\n" +
-		"1. **格式统一**：请严格遵守下方的【输出格式范例】，保持版面整洁。\n" +
-		"2. **介绍**：一句话简要说明该命令的核心功能。\n" +
-		"3. **位置**：必须输出一行 `位置: [程序路径]`（路径由用户提供）。\n" +
-		"4. **版本**：从提供的版本信息中提取版本号。如果提供了版本信息，在位置下方单列一行 `版本: x.y.z`。如果未提供，则不显示。\n" +
-		"5. **帮助原文**：翻译并整理原始帮助文档中的所有选项和用法说明。保留参数名原样，解释翻译为中文。\n" +
-		"6. **常用示例**：提供 3-5 个最常用的实战命令示例，并附带简短中文说明。\n" +
-		"7. **清洗噪音**：如果原始文档包含“非法选项”、“错误”等无关信息，请忽略它们。\n" +
-		"8. **严禁 Markdown**：绝对不要使用 markdown 格式。输出必须是纯文本。\n\n" +
-		"【输出格式范例】\n" +
-		"介绍: 分布式版本控制工具\n" +
-		"位置: /usr/bin/git\n" +
-		"版本: 2.39.0\n\n" +
-		"帮助原文:\n" +
-		"  用法: git [--version] [--help] <command> [<args>]\n" +
-		"  选项:\n" +
-		"    --version   显示版本信息\n" +
-		"    -h, --help  显示帮助信息\n" +
-		"    -C <path>   指定工作路径\n" +
-		"    ... (列出所有选项)\n\n" +
-		"常用示例:\n" +
-		"  git status            # 查看仓库状态\n" +
-		"  git commit -m 'msg'   # 提交更改"
+// lookupPrompt 根据查询场景选择提示词模板
+func lookupPrompt(useConcise, isMissing bool, subQuery string) string {
+	switch {
+	case isMissing:
+		return PromptLookupMissing
+	case subQuery != "":
+		return PromptLookupSubcommand
+	case useConcise:
+		return PromptLookupConcise
+	default:
+		return PromptLookupFull
+	}
 }
 
 func (c *Client) buildUserPrompt(osname, usedCmd, helpOut, verOut, subQuery, cmdPath string, isMissing, useConcise bool) string {
 	if isMissing {
-		return fmt.Sprintf("我的系统环境是%s\n我想要查询的命令是: %s (该命令在本地未安装)", osname, usedCmd)
+		return c.t("prompt.lookup_missing", osname, usedCmd, cmdPath)
 	}
 
-	mainCmd := usedCmd // 默认为使用的帮助指令
-	if strings.Contains(usedCmd, " ") {
-		mainCmd = strings.Split(usedCmd, " ")[0]
-	}
+	mainCmd := programName(usedCmd)
+
+	content := c.t("prompt.lookup", osname, mainCmd, cmdPath, usedCmd, helpOut)
 
-	content := fmt.Sprintf("我的系统环境是%s\n主命令: %s\n安装位置: %s\n执行的帮助指令: %s\n\n帮助文档内容:\n%s", osname, mainCmd, cmdPath, usedCmd, helpOut)
-	
 	if subQuery != "" {
-		content += fmt.Sprintf("\n\n**我具体想了解的子命令/参数是**: %s", subQuery)
+		content += "\n\n" + c.t("prompt.lookup_subquery", subQuery)
 	} else if verOut != "" {
-		content += fmt.Sprintf("\n\n这是版本查询输出:\n%s", verOut)
+		content += "\n\n" + c.t("prompt.lookup_version", verOut)
 	}
 	return content
 }
+
+// programName 从命令行中提取程序名，如 "/usr/bin/git --help" -> "git"
+func programName(cmdline string) string {
+	fields := strings.Fields(cmdline)
+	if len(fields) == 0 {
+		return ""
+	}
+	// Shell 内置命令的 help cd 和只有手册时的 man find，程序名是第二个词
+	if len(fields) > 1 && (fields[0] == "help" || fields[0] == "man" || fields[0] == "info") {
+		return filepath.Base(fields[1])
+	}
+	return filepath.Base(fields[0])
+}
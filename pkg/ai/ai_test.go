@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"ghp/pkg/i18n"
+)
+
+// recordProvider 记录收到的请求并返回固定的回答
+type recordProvider struct {
+	reqs    []Request
+	content string
+}
+
+func (p *recordProvider) Complete(_ context.Context, req Request) (string, error) {
+	p.reqs = append(p.reqs, req)
+	return p.content, nil
+}
+
+func (p *recordProvider) Stream(context.Context, Request) (Stream, error) {
+	return nil, errors.New("not supported")
+}
+
+// 发给模型的提示词跟随回答语言 (--lang)，与界面语言无关
+func TestPromptLanguage(t *testing.T) {
+	lang := i18n.Language()
+	i18n.SetLanguage("zh")
+	t.Cleanup(func() { i18n.SetLanguage(lang) })
+
+	tests := []struct {
+		lang string
+		want []string
+	}{
+		{"en", []string{"My system is", "Full command entered by the user", "[Output format]"}},
+		{"zh", []string{"我的系统环境是", "用户输入的完整命令", "【输出格式】"}},
+		{"", []string{"我的系统环境是", "【输出格式】"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			p := &recordProvider{content: `{"command":"ls -l","location":"/bin/ls","parts":[],"summary":"","suggestions":[],"risk":{"level":"safe","reason":""}}`}
+			c := NewClient(p, Options{Language: tt.lang})
+			if _, err := c.ExplainCommand(context.Background(), false, "ls -l", "help", "/bin/ls"); err != nil {
+				t.Fatal(err)
+			}
+			var prompt strings.Builder
+			for _, m := range p.reqs[0].Messages {
+				prompt.WriteString(m.Content + "\n")
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(prompt.String(), s) {
+					t.Errorf("提示词中缺少 %q:\n%s", s, prompt.String())
+				}
+			}
+		})
+	}
+}
+
+// 提示词中使用的文案在中英文中都必须存在 (缺少中文时会回退为英文)
+func TestPromptMessages(t *testing.T) {
+	keys := []string{
+		"prompt.help_command", "prompt.lookup", "prompt.lookup_missing", "prompt.lookup_subquery",
+		"prompt.lookup_version", "prompt.vetted", "prompt.vetted_rule", "prompt.explain",
+		"prompt.generate", "prompt.repair", "prompt.schema",
+	}
+	for _, key := range keys {
+		en, zh := i18n.TFor("en", key), i18n.TFor("zh", key)
+		if en == key {
+			t.Errorf("en 缺少文案 %s", key)
+		}
+		if zh == en {
+			t.Errorf("zh 缺少文案 %s", key)
+		}
+	}
+}
+
+func TestDecodeAnswerError(t *testing.T) {
+	lang := i18n.Language()
+	i18n.SetLanguage("en")
+	t.Cleanup(func() { i18n.SetLanguage(lang) })
+
+	err := decodeAnswer("not json", &CheatSheet{})
+	if err == nil || !strings.HasPrefix(err.Error(), "the model did not return valid JSON") {
+		t.Errorf("decodeAnswer() = %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"ghp/pkg/cache"
+)
+
+// 回答模式，用于区分缓存及按模式选择配置
+const (
+	ModeLookup   = "lookup"   // 常规速查 (AnalyzeHelpDoc)
+	ModeAnalyze  = "analyze"  // 命令解析 (ExplainCommand, -a)
+	ModeGenerate = "generate" // 命令生成 (GenerateCommand, -g)
+)
+
+// DefaultAnswerTTL 回答缓存的默认有效期
+const DefaultAnswerTTL = 7 * 24 * time.Hour
+
+// answerEntry 缓存的最终回答
+type answerEntry struct {
+	Mode      string    `json:"mode"`
+	Model     string    `json:"model"`
+	Program   string    `json:"program"`
+	Answer    string    `json:"answer"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// answerCache 按 模式 + 模型 + 提示词哈希 + 帮助文档哈希 寻址的回答缓存
+type answerCache struct {
+	store   *cache.Store
+	ttl     time.Duration
+	refresh bool
+}
+
+// EnableAnswerCache 开启回答缓存
+// refresh 为 true 时忽略已有缓存并用新回答覆盖
+func (c *Client) EnableAnswerCache(ttl time.Duration, refresh bool) error {
+	store, err := cache.Open(cache.NamespaceAnswers)
+	if err != nil {
+		return err
+	}
+	c.answers = &answerCache{store: store, ttl: ttl, refresh: refresh}
+	return nil
+}
+
+func answerKey(mode, model, prompt, helpDoc string) string {
+	return mode + ":" + model + ":" + hashString(prompt) + ":" + hashString(helpDoc)
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *answerCache) get(key string) (string, bool) {
+	if a == nil || a.refresh {
+		return "", false
+	}
+	var entry answerEntry
+	if hit, _ := a.store.Get(key, &entry); !hit {
+		a.store.RecordLookup(false)
+		return "", false
+	}
+	if a.ttl > 0 && time.Since(entry.CreatedAt) > a.ttl {
+		a.store.Delete(key)
+		a.store.RecordLookup(false)
+		return "", false
+	}
+	a.store.RecordLookup(true)
+	return entry.Answer, true
+}
+
+func (a *answerCache) put(key string, entry answerEntry) {
+	if a == nil || entry.Answer == "" {
+		return
+	}
+	entry.CreatedAt = time.Now()
+	a.store.Put(key, entry)
+}
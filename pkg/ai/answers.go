@@ -0,0 +1,152 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+
+	"ghp/pkg/i18n"
+)
+
+// 以下为各模式的结构化回答，通过 JSON Schema 约束模型输出，
+// 由终端渲染器排版展示，也可以通过 --output json 供脚本、编辑器插件直接消费
+
+// Option 一个命令行选项
+type Option struct {
+	Flag        string `json:"flag" jsonschema_description:"选项写法，保留原样，如 -a, --all 或 -o <file>"`
+	Description string `json:"description" jsonschema_description:"选项的简短说明"`
+}
+
+// Example 一条示例命令
+type Example struct {
+	Command     string `json:"command" jsonschema_description:"可直接执行的示例命令"`
+	Description string `json:"description" jsonschema_description:"示例的作用说明"`
+
+	TLDR bool `json:"tldr,omitempty" jsonschema:"-"` // 与本地 tldr 页面中的示例一致，由 ghp 标记
+}
+
+// CommandPart 命令中的一个组成部分 (子命令、参数、参数值)
+type CommandPart struct {
+	Token   string `json:"token" jsonschema_description:"命令中的原始片段，如 commit、-m 'msg'"`
+	Meaning string `json:"meaning" jsonschema_description:"该片段的含义"`
+}
+
+// CheatSheet 程序速查表 (常规查询)
+type CheatSheet struct {
+	Summary  string    `json:"summary" jsonschema_description:"一句话介绍该命令的核心功能"`
+	Location string    `json:"location" jsonschema_description:"程序路径，使用用户提供的值"`
+	Version  string    `json:"version" jsonschema_description:"从版本信息中提取的版本号，未提供时为空字符串"`
+	Install  []string  `json:"install" jsonschema_description:"命令未安装时的推荐安装方式，已安装时为空数组"`
+	Usage    []string  `json:"usage" jsonschema_description:"用法行，精简模式下可为空数组"`
+	Options  []Option  `json:"options" jsonschema_description:"选项列表"`
+	Examples []Example `json:"examples" jsonschema_description:"3-5 个常用示例"`
+
+	Offline bool `json:"offline,omitempty" jsonschema:"-"` // AI 不可用时由本地解析帮助文档生成
+}
+
+// 子命令卡片的识别结果
+const (
+	SubcommandOK              = "ok"               // 有效的子命令/参数
+	SubcommandNaturalLanguage = "natural_language" // 用户输入的是自然语言描述
+	SubcommandInvalid         = "invalid"          // 无效的子命令/参数
+)
+
+// SubcommandCard 子命令/参数卡片 (带子查询的常规查询)
+type SubcommandCard struct {
+	Kind       string    `json:"kind" jsonschema:"enum=ok,enum=natural_language,enum=invalid" jsonschema_description:"识别结果"`
+	Subcommand string    `json:"subcommand" jsonschema_description:"用户查询的子命令或参数"`
+	Summary    string    `json:"summary" jsonschema_description:"一句话说明其作用"`
+	Options    []Option  `json:"options" jsonschema_description:"与该子命令相关的常用选项，可为空数组"`
+	Examples   []Example `json:"examples" jsonschema_description:"2-3 个常用用法"`
+
+	Offline bool `json:"offline,omitempty" jsonschema:"-"` // AI 不可用时由本地解析帮助文档生成
+}
+
+// CommandExplanation 命令解析结果 (-a)
+type CommandExplanation struct {
+	Command     string        `json:"command" jsonschema_description:"用户输入的完整命令"`
+	Location    string        `json:"location" jsonschema_description:"程序路径，使用用户提供的值"`
+	Parts       []CommandPart `json:"parts" jsonschema_description:"逐层拆解的命令组成部分"`
+	Summary     string        `json:"summary" jsonschema_description:"一句话总结命令执行后会发生什么"`
+	Suggestions []string      `json:"suggestions" jsonschema_description:"1-2 条优化建议或后续操作"`
+	Risk        Risk          `json:"risk" jsonschema_description:"执行该命令的风险评估"`
+}
+
+// GeneratedCommand 命令生成结果 (-g)
+type GeneratedCommand struct {
+	Request     string        `json:"request" jsonschema_description:"用户的需求描述"`
+	Location    string        `json:"location" jsonschema_description:"程序路径，使用用户提供的值"`
+	Command     string        `json:"command" jsonschema_description:"推荐执行的完整命令，不要使用绝对路径"`
+	Parts       []CommandPart `json:"parts" jsonschema_description:"命令中关键参数的解释"`
+	Suggestions []string      `json:"suggestions" jsonschema_description:"注意事项或下一步操作建议"`
+	Risk        Risk          `json:"risk" jsonschema_description:"执行该命令的风险评估"`
+
+	Verification *Verification `json:"verification,omitempty" jsonschema:"-"` // 由 ghp 在本地校验，不由模型生成
+}
+
+// Verification 生成的命令与帮助文档的比对结果
+type Verification struct {
+	Repaired bool     `json:"repaired"`           // 原命令存在未知选项，已请模型修正
+	Original string   `json:"original,omitempty"` // 修正前的命令
+	Flagged  []string `json:"flagged,omitempty"`  // 原命令中帮助文档里找不到的选项/子命令
+	Unknown  []string `json:"unknown,omitempty"`  // 最终命令中仍找不到的选项/子命令
+	// 帮助文档只列出常用子命令，最终命令中未列出因而无法校验的子命令
+	Unverified []string `json:"unverified,omitempty"`
+}
+
+// Risk 命令的风险评估，由模型给出，ghp 再用本地规则校正 (只会调高等级)
+type Risk struct {
+	Level  string   `json:"level" jsonschema:"enum=safe,enum=modifies_state,enum=destructive,enum=irreversible" jsonschema_description:"风险等级: safe 只读不改变任何状态; modifies_state 修改文件、配置或远端状态但可以撤销; destructive 删除或覆盖数据; irreversible 造成无法恢复的后果，如删除整个目录树、强制推送、格式化磁盘"`
+	Reason string   `json:"reason" jsonschema_description:"一句话说明风险所在，safe 时为空字符串"`
+	Rules  []string `json:"rules,omitempty" jsonschema:"-"` // 命中的本地规则说明
+}
+
+// Answer 结构化回答
+type Answer interface {
+	isAnswer()
+}
+
+func (*CheatSheet) isAnswer()         {}
+func (*SubcommandCard) isAnswer()     {}
+func (*CommandExplanation) isAnswer() {}
+func (*GeneratedCommand) isAnswer()   {}
+
+// Schema 要求模型按 JSON Schema 输出结构化结果
+type Schema struct {
+	Name   string
+	Schema json.RawMessage
+}
+
+// schemaFor 根据结构体指针生成 JSON Schema，以结构体名作为 Schema 名称
+// 所有字段均为必填且不允许额外字段，满足 OpenAI strict 模式的要求
+func schemaFor(v any) *Schema {
+	name := reflect.TypeOf(v).Elem().Name()
+	r := &jsonschema.Reflector{
+		Anonymous:      true,
+		DoNotReference: true,
+	}
+	s := r.Reflect(v)
+	s.Version = ""
+	data, err := json.Marshal(s)
+	if err != nil {
+		panic(fmt.Sprintf("ai: 无法生成 %s 的 JSON Schema: %v", name, err))
+	}
+	return &Schema{Name: name, Schema: data}
+}
+
+// decodeAnswer 解析模型返回的 JSON，兼容被 ``` 代码块包裹的情况
+func decodeAnswer(content string, v any) error {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+	}
+	if err := json.Unmarshal([]byte(content), v); err != nil {
+		return fmt.Errorf(i18n.T("ai.err_invalid_json"), err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"ghp/pkg/i18n"
+)
+
+const (
+	anthropicVersion   = "2023-06-01"
+	anthropicMaxTokens = 4096
+)
+
+// anthropicProvider Anthropic Messages API 原生后端
+type anthropicProvider struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewAnthropicProvider 创建 Anthropic Messages API 后端
+// baseURL 形如 https://api.anthropic.com/v1
+func NewAnthropicProvider(apiKey, baseURL string) Provider {
+	return &anthropicProvider{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Temperature float32            `json:"temperature"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+type anthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func (e *anthropicError) toAPIError(statusCode int) *APIError {
+	return &APIError{Provider: "anthropic", StatusCode: statusCode, Type: e.Type, Message: e.Message}
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// anthropicEvent 流式响应中 data 字段的公共结构
+type anthropicEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error *anthropicError `json:"error"`
+}
+
+func (p *anthropicProvider) Complete(ctx context.Context, req Request) (string, error) {
+	resp, err := p.do(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, block := range out.Content {
+		if block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+	if sb.Len() == 0 {
+		return "", errEmptyChoices()
+	}
+	return sb.String(), nil
+}
+
+func (p *anthropicProvider) Stream(ctx context.Context, req Request) (Stream, error) {
+	resp, err := p.do(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	return &anthropicStream{body: resp.Body, reader: bufio.NewReader(resp.Body)}, nil
+}
+
+// do 发送请求，非 2xx 响应会被转换为错误
+func (p *anthropicProvider) do(ctx context.Context, req Request, stream bool) (*http.Response, error) {
+	body := anthropicRequest{
+		Model:       req.Model,
+		MaxTokens:   anthropicMaxTokens,
+		Temperature: req.Temperature,
+		Stream:      stream,
+	}
+	// Messages API 的 system 提示词是顶层字段，不能放在 messages 中
+	var system []string
+	for _, m := range req.Messages {
+		if m.Role == RoleSystem {
+			system = append(system, m.Content)
+			continue
+		}
+		body.Messages = append(body.Messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+	body.System = strings.Join(system, "\n\n")
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		var errResp struct {
+			Error *anthropicError `json:"error"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error != nil {
+			return nil, errResp.Error.toAPIError(resp.StatusCode)
+		}
+		return nil, &APIError{Provider: "anthropic", StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	return resp, nil
+}
+
+// anthropicStream 解析 Messages API 的 SSE 事件流
+type anthropicStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+	done   bool
+}
+
+func (s *anthropicStream) Recv() (string, error) {
+	for {
+		if s.done {
+			return "", io.EOF
+		}
+		data, err := s.nextEvent()
+		if err != nil {
+			return "", err
+		}
+		if data == "" {
+			continue
+		}
+
+		var ev anthropicEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return "", err
+		}
+		switch ev.Type {
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" {
+				return ev.Delta.Text, nil
+			}
+		case "message_stop":
+			s.done = true
+		case "error":
+			if ev.Error != nil {
+				return "", ev.Error.toAPIError(0)
+			}
+			return "", errors.New(i18n.T("ai.err_anthropic_stream", data))
+		}
+		// message_start、content_block_start、ping 等事件不携带文本，忽略
+	}
+}
+
+// nextEvent 读取下一个以空行结尾的 SSE 事件，返回其 data 内容
+func (s *anthropicStream) nextEvent() (string, error) {
+	var data []string
+	for {
+		line, err := s.reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if len(data) > 0 {
+				return strings.Join(data, "\n"), nil
+			}
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+		if err != nil {
+			if len(data) > 0 {
+				return strings.Join(data, "\n"), nil
+			}
+			return "", err
+		}
+	}
+}
+
+func (s *anthropicStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAnthropicStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    string
+		want      string
+		wantErr   string
+		errorType string
+	}{
+		{
+			name: "message_stop",
+			events: "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\"}}\n\n" +
+				"event: content_block_start\ndata: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n" +
+				"event: ping\ndata: {\"type\":\"ping\"}\n\n" +
+				"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"{\\\"summary\"}}\n\n" +
+				"event: content_block_delta\r\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"\\\":1}\"}}\r\n\r\n" +
+				"event: content_block_stop\ndata: {\"type\":\"content_block_stop\",\"index\":0}\n\n" +
+				"event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"}}\n\n" +
+				"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n" +
+				"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"ignored\"}}\n\n",
+			want: `{"summary":1}`,
+		},
+		{
+			name:   "eof without trailing blank line",
+			events: "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"tail\"}}",
+			want:   "tail",
+		},
+		{
+			name: "error event",
+			events: "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"par\"}}\n\n" +
+				"event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n",
+			want:      "par",
+			wantErr:   "Overloaded",
+			errorType: "overloaded_error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got anthropicRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "key" || r.Header.Get("anthropic-version") == "" {
+					t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+				}
+				json.NewDecoder(r.Body).Decode(&got)
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprint(w, tt.events)
+			}))
+			defer srv.Close()
+
+			stream, err := NewAnthropicProvider("key", srv.URL+"/v1").Stream(context.Background(), Request{
+				Model:    "claude-sonnet-4-5",
+				Messages: []Message{{Role: RoleSystem, Content: "sys"}, {Role: RoleUser, Content: "hi"}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			text, err := readStream(stream)
+			if text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			var apiErr *APIError
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (!errors.As(err, &apiErr) || apiErr.Message != tt.wantErr || apiErr.Type != tt.errorType):
+				t.Errorf("err = %v, want APIError %s %q", err, tt.errorType, tt.wantErr)
+			}
+			// system 提示词是顶层字段，不在 messages 中
+			if !got.Stream || got.System != "sys" || len(got.Messages) != 1 || got.Messages[0].Role != RoleUser {
+				t.Errorf("request = %+v", got)
+			}
+		})
+	}
+}
+
+func TestAnthropicHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`)
+	}))
+	defer srv.Close()
+
+	_, err := NewAnthropicProvider("bad", srv.URL).Stream(context.Background(), Request{Model: "m"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Type != "authentication_error" {
+		t.Errorf("err = %v, want a 401 authentication_error", err)
+	}
+}
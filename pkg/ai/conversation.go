@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"ghp/pkg/i18n"
+)
+
+// Conversation 基于最近一次回答的多轮追问
+// 保留系统提示词、帮助文档上下文和之前的回答，追问时无需重新获取帮助文档
+type Conversation struct {
+	client   *Client
+	messages []Message
+}
+
+// Conversation 以最近一次回答为上下文开始追问
+func (c *Client) Conversation() (*Conversation, error) {
+	if len(c.last) == 0 {
+		return nil, errors.New(i18n.T("ai.err_no_conversation"))
+	}
+	note, err := c.prompt(PromptFollowUp)
+	if err != nil {
+		return nil, err
+	}
+	return &Conversation{
+		client:   c,
+		messages: append(slices.Clone(c.last), Message{Role: RoleSystem, Content: note}),
+	}, nil
+}
+
+// Ask 发送一个追问，回答通过 onDelta 逐段返回 (非流式时一次性返回)
+// 请求失败时不记录该问题，可以直接重试
+func (cv *Conversation) Ask(ctx context.Context, useStream bool, question string, onDelta func(string)) error {
+	c := cv.client
+	messages := append(slices.Clip(cv.messages), Message{Role: RoleUser, Content: question})
+	req := Request{
+		Model:       c.opts.Model,
+		Messages:    messages,
+		Temperature: c.opts.Temperature,
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var content string
+	var err error
+	if useStream {
+		content, err = c.receiveStream(ctx, req, onDelta)
+	} else {
+		content, err = c.provider.Complete(ctx, req)
+		if err == nil {
+			onDelta(content)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	cv.messages = append(messages, Message{Role: RoleAssistant, Content: content})
+	return nil
+}
@@ -0,0 +1,182 @@
+package ai
+
+import (
+	"runtime"
+	"strings"
+)
+
+// standardTools 跨平台通用、支持 --help 和 --version 的常见工具
+var standardTools = []string{
+	// 版本控制
+	"git", "git-lfs", "tig", "lazygit", "gitui", "delta", "pre-commit", "git-cliff",
+	// 编程语言与运行时
+	"python", "python3", "pip", "pip3", "pipx", "poetry", "pdm", "uv", "ruff", "black", "isort", "mypy", "pytest", "tox", "flake8", "pylint",
+	"node", "npm", "npx", "yarn", "pnpm", "deno", "bun", "tsc", "eslint", "prettier", "vite", "webpack", "esbuild",
+	"rustc", "cargo", "rustup", "rustfmt", "ruby", "gem", "bundle", "rake", "rails",
+	"gradle", "elixir", "ghc", "cabal", "stack",
+	"swift", "nim", "crystal", "julia", "Rscript", "golangci-lint", "goreleaser", "protoc", "buf",
+	// 编译构建
+	"gcc", "g++", "cc", "c++", "clang", "clang++", "clang-format", "clang-tidy", "make", "cmake", "meson", "ccache", "gdb", "lldb", "valgrind", "strace", "ltrace",
+	// 容器与云原生
+	"docker", "docker-compose", "podman", "buildah", "skopeo", "nerdctl", "crictl", "ctr", "colima", "limactl",
+	"kubelet", "stern",
+	"tofu", "packer", "vault", "consul", "nomad", "ansible", "ansible-playbook", "ansible-galaxy", "vagrant", "az", "heroku", "vercel", "netlify", "wrangler",
+	// 数据库
+	"mysql", "mysqldump", "mysqladmin", "psql", "pg_dump", "pg_restore", "pg_ctl", "createdb", "dropdb", "redis-cli", "redis-server", "mongosh", "mongodump", "mongorestore", "clickhouse-client", "litecli", "pgcli", "mycli",
+	// 文本处理与搜索
+	"jq", "yq", "gojq", "rg", "fd", "fzf", "ag", "ack", "bat", "exa", "eza", "lsd", "sd", "xsv", "csvlook", "mlr", "pandoc", "shellcheck", "shfmt", "yamllint", "hadolint", "markdownlint",
+	// 网络
+	"curl", "wget", "http", "xh", "aria2c", "rsync", "rclone", "mosh", "nmap", "iperf3", "mtr", "tcpdump", "tshark", "websocat", "cloudflared", "wg", "openvpn",
+	// 编辑器与终端工具
+	"vim", "nvim", "nano", "emacs", "hx", "code", "subl", "less", "zellij", "htop", "btop", "glances", "ncdu", "dust", "tldr", "navi", "mise", "nvm", "starship", "zoxide", "atuin", "watchexec", "just", "task", "hyperfine", "tokei", "cloc", "scc",
+	// 多媒体与文档
+	"gs", "qpdf", "yt-dlp", "youtube-dl", "sox", "lame", "flac", "jpegoptim", "pngquant", "mmdc", "jekyll", "mkdocs", "sphinx-build",
+	// 安全与证书
+	"gpg", "gpg2", "age", "certbot", "trivy", "grype", "syft", "sops",
+	// 压缩归档
+	"xz", "zstd", "lz4", "brotli", "pigz",
+}
+
+// gnuTools Linux 下由 GNU coreutils/util-linux 等提供、支持 --help 和 --version 的工具
+// macOS 等系统上同名工具为 BSD 版本，通常不支持长参数，因此仅在 Linux 使用
+var gnuTools = []string{
+	"ls", "cp", "mv", "rm", "mkdir", "rmdir", "ln", "chmod", "chown", "chgrp", "touch", "cat", "tac", "head", "tail", "wc", "sort", "uniq", "cut", "paste", "join", "tr", "tee", "split", "csplit",
+	"df", "du", "stat", "readlink", "realpath", "basename", "dirname", "mktemp", "install", "shred", "truncate", "dd", "sync",
+	"date", "sleep", "timeout", "env", "printenv", "nohup", "nice", "id", "whoami", "groups", "uname", "hostname", "uptime", "who", "users", "tty", "stty",
+	"seq", "yes", "factor", "expr", "numfmt", "printf", "od", "hexdump", "base32", "base64", "md5sum", "sha1sum", "sha256sum", "sha512sum", "b2sum", "cksum", "shuf", "fmt", "fold", "nl", "pr", "expand", "unexpand", "comm", "diff", "diff3", "sdiff", "cmp", "patch",
+	"grep", "egrep", "fgrep", "sed", "awk", "gawk", "find", "xargs", "locate", "updatedb", "file",
+	"tar", "gzip", "gunzip", "zcat", "bzip2", "bunzip2", "cpio",
+	"ps", "pgrep", "pkill", "kill", "free", "vmstat", "pmap", "watch", "w", "slabtop", "sysctl",
+	"mount", "umount", "lsblk", "blkid", "findmnt", "fdisk", "sfdisk", "parted", "mkfs", "fsck", "losetup", "swapon", "swapoff", "lscpu", "lsmem", "lsns", "nsenter", "unshare", "chroot", "flock", "ionice", "taskset", "chrt", "prlimit", "dmesg", "hwclock", "column", "script", "more", "wall", "logger", "look", "rev",
+	"systemctl", "journalctl", "loginctl", "hostnamectl", "timedatectl", "localectl", "networkctl", "resolvectl", "busctl", "systemd-analyze",
+	"useradd", "userdel", "usermod", "groupadd", "groupdel", "passwd", "chage", "su", "sudo", "visudo",
+	"ss", "nmcli", "ethtool", "iptables", "ip6tables", "nft", "ufw", "firewall-cmd",
+	"apt", "apt-get", "apt-cache", "dpkg", "dnf", "yum", "rpm", "zypper", "snap", "flatpak", "apk",
+	"bash", "zsh", "fish",
+}
+
+// knownExceptions 不遵循 --help/--version 约定的常见工具 (帮助命令, 版本命令)
+// 版本命令为空表示该工具没有版本查询方式
+var knownExceptions = map[string][2]string{
+	"go":          {"go help", "go version"},
+	"java":        {"java -help", "java -version"},
+	"javac":       {"javac -help", "javac -version"},
+	"jar":         {"jar --help", "jar --version"},
+	"mvn":         {"mvn --help", "mvn --version"},
+	"kubectl":     {"kubectl --help", "kubectl version --client"},
+	"helm":        {"helm --help", "helm version"},
+	"minikube":    {"minikube --help", "minikube version"},
+	"kind":        {"kind --help", "kind version"},
+	"k9s":         {"k9s --help", "k9s version"},
+	"oc":          {"oc --help", "oc version --client"},
+	"eksctl":      {"eksctl --help", "eksctl version"},
+	"gcloud":      {"gcloud --help", "gcloud version"},
+	"aws":         {"aws help", "aws --version"},
+	"gh":          {"gh --help", "gh --version"},
+	"glab":        {"glab --help", "glab version"},
+	"openssl":     {"openssl help", "openssl version"},
+	"ssh":         {"ssh -h", "ssh -V"},
+	"scp":         {"scp -h", ""},
+	"sftp":        {"sftp -h", ""},
+	"ssh-agent":   {"ssh-agent -h", ""},
+	"ffmpeg":      {"ffmpeg -h", "ffmpeg -version"},
+	"ffprobe":     {"ffprobe -h", "ffprobe -version"},
+	"ffplay":      {"ffplay -h", "ffplay -version"},
+	"php":         {"php -h", "php -v"},
+	"perl":        {"perl -h", "perl -v"},
+	"lua":         {"lua -h", "lua -v"},
+	"luajit":      {"luajit -h", "luajit -v"},
+	"sqlite3":     {"sqlite3 -help", "sqlite3 -version"},
+	"svn":         {"svn help", "svn --version"},
+	"hg":          {"hg help", "hg --version"},
+	"brew":        {"brew help", "brew --version"},
+	"port":        {"port help", "port version"},
+	"pacman":      {"pacman -h", "pacman -V"},
+	"yay":         {"yay -h", "yay -V"},
+	"nginx":       {"nginx -h", "nginx -v"},
+	"apachectl":   {"apachectl -h", "apachectl -v"},
+	"httpd":       {"httpd -h", "httpd -v"},
+	"caddy":       {"caddy help", "caddy version"},
+	"dig":         {"dig -h", "dig -v"},
+	"host":        {"host", "host -V"},
+	"ping":        {"ping -h", "ping -V"},
+	"ip":          {"ip help", "ip -V"},
+	"tmux":        {"tmux -h", "tmux -V"},
+	"screen":      {"screen -h", "screen -v"},
+	"zip":         {"zip -h", "zip -v"},
+	"unzip":       {"unzip -h", "unzip -v"},
+	"ninja":       {"ninja -h", "ninja --version"},
+	"terraform":   {"terraform -help", "terraform -version"},
+	"dotnet":      {"dotnet --help", "dotnet --version"},
+	"conda":       {"conda --help", "conda --version"},
+	"nc":          {"nc -h", ""},
+	"ncat":        {"ncat -h", "ncat --version"},
+	"crontab":     {"crontab -h", ""},
+	"tree":        {"tree --help", "tree --version"},
+	"help":        {"help help", ""},
+	"cd":          {"help cd", ""},
+	"alias":       {"help alias", ""},
+	"export":      {"help export", ""},
+	"source":      {"help source", ""},
+	"ulimit":      {"help ulimit", ""},
+	"umask":       {"help umask", ""},
+	"jobs":        {"help jobs", ""},
+	"history":     {"help history", ""},
+	"type":        {"help type", ""},
+	"trap":        {"help trap", ""},
+	"set":         {"help set", ""},
+	"read":        {"help read", ""},
+	"pushd":       {"help pushd", ""},
+	"popd":        {"help popd", ""},
+	"fg":          {"help fg", ""},
+	"bg":          {"help bg", ""},
+	"curl-config": {"curl-config --help", "curl-config --version"},
+	"lsof":        {"lsof -h", "lsof -v"},
+	"zig":         {"zig --help", "zig version"},
+	"kubeadm":     {"kubeadm --help", "kubeadm version"},
+	"kustomize":   {"kustomize --help", "kustomize version"},
+	"skaffold":    {"skaffold --help", "skaffold version"},
+	"tilt":        {"tilt --help", "tilt version"},
+	"argocd":      {"argocd --help", "argocd version --client"},
+	"flux":        {"flux --help", "flux version --client"},
+	"istioctl":    {"istioctl --help", "istioctl version --remote=false"},
+	"pulumi":      {"pulumi --help", "pulumi version"},
+	"hugo":        {"hugo --help", "hugo version"},
+	"direnv":      {"direnv --help", "direnv version"},
+	"socat":       {"socat -h", "socat -V"},
+	"convert":     {"convert -help", "convert -version"},
+	"magick":      {"magick -help", "magick -version"},
+	"identify":    {"identify -help", "identify -version"},
+	"dot":         {"dot -?", "dot -V"},
+	"7z":          {"7z --help", ""},
+}
+
+var (
+	standardToolSet = toSet(standardTools)
+	gnuToolSet      = toSet(gnuTools)
+)
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// KnownHelpCommand 从内置表中查找当前系统上常见工具的帮助/版本命令，不需要询问 AI
+func KnownHelpCommand(program string) ([]string, []string, bool) {
+	return lookupKnownHelpCommand(runtime.GOOS, program)
+}
+
+// lookupKnownHelpCommand 从内置表中查找常见工具的帮助/版本命令
+// 返回 (帮助命令, 版本命令, 是否命中)
+func lookupKnownHelpCommand(osname, program string) ([]string, []string, bool) {
+	if cmds, ok := knownExceptions[program]; ok {
+		return strings.Fields(cmds[0]), strings.Fields(cmds[1]), true
+	}
+	if standardToolSet[program] || (osname == "linux" && gnuToolSet[program]) {
+		return []string{program, "--help"}, []string{program, "--version"}, true
+	}
+	return nil, nil, false
+}
@@ -0,0 +1,221 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"ghp/pkg/i18n"
+)
+
+// ollamaProvider Ollama 原生 /api/chat 后端，适用于完全离线的环境
+type ollamaProvider struct {
+	baseURL    string
+	httpClient *http.Client
+
+	once         sync.Once
+	defaultModel string
+	discoverErr  error
+}
+
+// NewOllamaProvider 创建 Ollama 后端
+// baseURL 形如 http://localhost:11434，请求未指定模型时自动使用本地已安装的第一个模型
+func NewOllamaProvider(baseURL string) Provider {
+	return &ollamaProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   json.RawMessage `json:"format,omitempty"` // 结构化输出时为 JSON Schema
+	Options  struct {
+		Temperature float32 `json:"temperature"`
+	} `json:"options"`
+}
+
+// ollamaChunk 非流式响应和 NDJSON 流中每一行的结构
+type ollamaChunk struct {
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+	Error   string        `json:"error"`
+}
+
+func (p *ollamaProvider) Complete(ctx context.Context, req Request) (string, error) {
+	resp, err := p.chat(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out ollamaChunk
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+	if out.Error != "" {
+		return "", &APIError{Provider: "ollama", Message: out.Error}
+	}
+	if out.Message.Content == "" {
+		return "", errEmptyChoices()
+	}
+	return out.Message.Content, nil
+}
+
+func (p *ollamaProvider) Stream(ctx context.Context, req Request) (Stream, error) {
+	resp, err := p.chat(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ollamaStream{body: resp.Body, scanner: newLineScanner(resp.Body)}, nil
+}
+
+func (p *ollamaProvider) chat(ctx context.Context, req Request, stream bool) (*http.Response, error) {
+	model, err := p.resolveModel(ctx, req.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	body := ollamaRequest{Model: model, Stream: stream}
+	if req.Schema != nil {
+		body.Format = req.Schema.Schema
+	}
+	body.Options.Temperature = req.Temperature
+	for _, m := range req.Messages {
+		body.Messages = append(body.Messages, ollamaMessage{Role: m.Role, Content: m.Content})
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return p.do(ctx, http.MethodPost, "/api/chat", bytes.NewReader(payload))
+}
+
+// resolveModel 未指定模型时通过 /api/tags 自动发现本地模型
+func (p *ollamaProvider) resolveModel(ctx context.Context, model string) (string, error) {
+	if model != "" {
+		return model, nil
+	}
+	p.once.Do(func() {
+		var models []string
+		models, p.discoverErr = p.models(ctx)
+		if p.discoverErr == nil {
+			if len(models) == 0 {
+				p.discoverErr = errors.New(i18n.T("ai.err_ollama_no_model"))
+			} else {
+				p.defaultModel = models[0]
+			}
+		}
+	})
+	return p.defaultModel, p.discoverErr
+}
+
+// models 列出本地已安装的模型
+func (p *ollamaProvider) models(ctx context.Context) ([]string, error) {
+	resp, err := p.do(ctx, http.MethodGet, "/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(out.Models))
+	for _, m := range out.Models {
+		names = append(names, m.Name)
+	}
+	return names, nil
+}
+
+// do 发送请求，非 2xx 响应会被转换为错误
+func (p *ollamaProvider) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		var errResp ollamaChunk
+		message := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
+			message = errResp.Error
+		}
+		return nil, &APIError{Provider: "ollama", StatusCode: resp.StatusCode, Message: message}
+	}
+	return resp, nil
+}
+
+// ollamaStream 解析 /api/chat 的 NDJSON 流，每行一个 JSON 对象
+type ollamaStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	done    bool
+}
+
+func (s *ollamaStream) Recv() (string, error) {
+	for {
+		if s.done {
+			return "", io.EOF
+		}
+		if !s.scanner.Scan() {
+			if err := s.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		line := bytes.TrimSpace(s.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return "", err
+		}
+		if chunk.Error != "" {
+			return "", &APIError{Provider: "ollama", Message: chunk.Error}
+		}
+		s.done = chunk.Done
+		if chunk.Message.Content != "" {
+			return chunk.Message.Content, nil
+		}
+	}
+}
+
+func (s *ollamaStream) Close() error {
+	return s.body.Close()
+}
+
+// newLineScanner 创建允许超长行的按行扫描器
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return scanner
+}
@@ -0,0 +1,161 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// readStream 读取流式响应的全部文本
+func readStream(s Stream) (string, error) {
+	defer s.Close()
+	var sb strings.Builder
+	for {
+		delta, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return sb.String(), err
+		}
+		sb.WriteString(delta)
+	}
+}
+
+// newOllamaServer 模拟 Ollama 服务：/api/tags 返回 models，/api/chat 按行返回 chunks
+func newOllamaServer(t *testing.T, models []string, chunks []string) (*httptest.Server, *ollamaRequest) {
+	t.Helper()
+	got := &ollamaRequest{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tags", func(w http.ResponseWriter, r *http.Request) {
+		var out struct {
+			Models []map[string]string `json:"models"`
+		}
+		for _, m := range models {
+			out.Models = append(out.Models, map[string]string{"name": m})
+		}
+		json.NewEncoder(w).Encode(out)
+	})
+	mux.HandleFunc("POST /api/chat", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, c := range chunks {
+			fmt.Fprintln(w, c)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func TestOllamaStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunks  []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "done",
+			chunks: []string{
+				`{"message":{"role":"assistant","content":"{\"sum"},"done":false}`,
+				``,
+				`{"message":{"role":"assistant","content":"mary\":1}"},"done":false}`,
+				`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop"}`,
+				`{"message":{"role":"assistant","content":"ignored"},"done":false}`,
+			},
+			want: `{"summary":1}`,
+		},
+		{
+			name:   "eof without done",
+			chunks: []string{`{"message":{"role":"assistant","content":"partial"},"done":false}`},
+			want:   "partial",
+		},
+		{
+			name: "error line",
+			chunks: []string{
+				`{"message":{"role":"assistant","content":"par"},"done":false}`,
+				`{"error":"model runner has unexpectedly stopped"}`,
+			},
+			want:    "par",
+			wantErr: "model runner has unexpectedly stopped",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, got := newOllamaServer(t, nil, tt.chunks)
+			stream, err := NewOllamaProvider(srv.URL+"/").Stream(context.Background(), Request{
+				Model:    "qwen2.5-coder:7b",
+				Messages: []Message{{Role: RoleSystem, Content: "sys"}, {Role: RoleUser, Content: "hi"}},
+				Schema:   schemaFor(&CheatSheet{}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			text, err := readStream(stream)
+			if text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			var apiErr *APIError
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (!errors.As(err, &apiErr) || apiErr.Message != tt.wantErr):
+				t.Errorf("err = %v, want APIError %q", err, tt.wantErr)
+			}
+			if !got.Stream || got.Model != "qwen2.5-coder:7b" || len(got.Messages) != 2 || len(got.Format) == 0 {
+				t.Errorf("request = %+v", got)
+			}
+		})
+	}
+}
+
+func TestOllamaModelDiscovery(t *testing.T) {
+	srv, got := newOllamaServer(t, []string{"llama3:latest", "qwen2.5:7b"}, []string{
+		`{"message":{"role":"assistant","content":"ok"},"done":true}`,
+	})
+	p := NewOllamaProvider(srv.URL)
+	content, err := p.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "ok" {
+		t.Errorf("content = %q, want %q", content, "ok")
+	}
+	if got.Model != "llama3:latest" {
+		t.Errorf("model = %q, want the first local model", got.Model)
+	}
+	if got.Stream {
+		t.Error("Complete should not request a stream")
+	}
+}
+
+func TestOllamaNoModels(t *testing.T) {
+	srv, _ := newOllamaServer(t, nil, nil)
+	_, err := NewOllamaProvider(srv.URL).Complete(context.Background(), Request{})
+	if err == nil {
+		t.Fatal("expected an error when no model is installed")
+	}
+}
+
+func TestOllamaHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":"model \"nope\" not found, try pulling it first"}`)
+	}))
+	defer srv.Close()
+
+	_, err := NewOllamaProvider(srv.URL).Stream(context.Background(), Request{Model: "nope"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || !strings.Contains(apiErr.Message, "not found") {
+		t.Errorf("err = %v, want a 404 APIError", err)
+	}
+}
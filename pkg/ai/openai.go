@@ -0,0 +1,145 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/sashabaranov/go-openai"
+
+	"ghp/pkg/i18n"
+)
+
+// errEmptyChoices 模型未返回任何内容
+func errEmptyChoices() error {
+	return errors.New(i18n.T("ai.err_empty"))
+}
+
+// 结构化输出的请求方式，兼容接口不支持时逐级降级；Schema 始终写在提示词中，不依赖 response_format
+const (
+	formatJSONSchema = iota // response_format: json_schema (strict)
+	formatJSONObject        // response_format: json_object
+	formatNone              // 不设置 response_format
+)
+
+// formatParam 后端拒绝 response_format 时错误信息中的关键词
+var formatParam = regexp.MustCompile(`(?i)response_format|json_schema|json_object|schema|structured output`)
+
+// openAIProvider OpenAI 兼容接口后端 (DeepSeek、通义千问等)
+type openAIProvider struct {
+	client *openai.Client
+	format int // 当前使用的结构化输出方式，被后端拒绝后降级，之后的请求沿用
+}
+
+// NewOpenAIProvider 创建 OpenAI 兼容接口后端
+func NewOpenAIProvider(cfg openai.ClientConfig) Provider {
+	return &openAIProvider{client: openai.NewClientWithConfig(cfg)}
+}
+
+func (p *openAIProvider) Complete(ctx context.Context, req Request) (string, error) {
+	var resp openai.ChatCompletionResponse
+	err := p.withFormat(req, func(r openai.ChatCompletionRequest) (err error) {
+		resp, err = p.client.CreateChatCompletion(ctx, r)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", errEmptyChoices()
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+func (p *openAIProvider) Stream(ctx context.Context, req Request) (Stream, error) {
+	var stream *openai.ChatCompletionStream
+	err := p.withFormat(req, func(r openai.ChatCompletionRequest) (err error) {
+		stream, err = p.client.CreateChatCompletionStream(ctx, r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &openAIStream{stream: stream}, nil
+}
+
+// withFormat 发送请求，response_format 被后端拒绝时 (如部分网关不支持 json_schema) 降级后重试
+func (p *openAIProvider) withFormat(req Request, send func(openai.ChatCompletionRequest) error) error {
+	for {
+		err := send(toOpenAIRequest(req, p.format))
+		if req.Schema == nil || p.format == formatNone || !formatRejected(err) {
+			return err
+		}
+		p.format++
+	}
+}
+
+// formatRejected 错误是否为后端不支持请求中的 response_format
+func formatRejected(err error) bool {
+	var status int
+	var detail string
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		status, detail = apiErr.HTTPStatusCode, apiErr.Message
+		if apiErr.Param != nil {
+			detail += " " + *apiErr.Param
+		}
+	case errors.As(err, &reqErr):
+		status, detail = reqErr.HTTPStatusCode, string(reqErr.Body)
+	default:
+		return false
+	}
+	return (status == http.StatusBadRequest || status == http.StatusUnprocessableEntity) && formatParam.MatchString(detail)
+}
+
+func toOpenAIRequest(req Request, format int) openai.ChatCompletionRequest {
+	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, openai.ChatCompletionMessage{Role: m.Role, Content: m.Content})
+	}
+	out := openai.ChatCompletionRequest{
+		Model:       req.Model,
+		Messages:    messages,
+		Temperature: req.Temperature,
+	}
+	switch {
+	case req.Schema == nil || format == formatNone:
+	case format == formatJSONObject:
+		out.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+	default:
+		out.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   req.Schema.Name,
+				Schema: req.Schema.Schema,
+				Strict: true,
+			},
+		}
+	}
+	return out
+}
+
+type openAIStream struct {
+	stream *openai.ChatCompletionStream
+}
+
+func (s *openAIStream) Recv() (string, error) {
+	for {
+		resp, err := s.stream.Recv()
+		if err != nil {
+			return "", err
+		}
+		// 部分兼容接口会发送不含 choices 的心跳/用量数据块，直接跳过
+		if len(resp.Choices) == 0 {
+			continue
+		}
+		return resp.Choices[0].Delta.Content, nil
+	}
+}
+
+func (s *openAIStream) Close() error {
+	return s.stream.Close()
+}
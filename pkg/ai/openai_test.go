@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// 不支持 json_schema 的网关返回 400，客户端应降级为 json_object，再降级为不设置 response_format
+func TestOpenAIResponseFormatFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		supported []string // 后端接受的 response_format，"" 表示不设置
+		want      []string // 依次发出的 response_format
+	}{
+		{"json_schema", []string{"json_schema", "json_object", ""}, []string{"json_schema"}},
+		{"json_object", []string{"json_object", ""}, []string{"json_schema", "json_object"}},
+		{"none", []string{""}, []string{"json_schema", "json_object", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					ResponseFormat *struct {
+						Type string `json:"type"`
+					} `json:"response_format"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+				format := ""
+				if req.ResponseFormat != nil {
+					format = req.ResponseFormat.Type
+				}
+				sent = append(sent, format)
+				if !slices.Contains(tt.supported, format) {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprintf(w, `{"error":{"message":"'response_format.type' %s is not supported","type":"invalid_request_error"}}`, format)
+					return
+				}
+				fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"{\"summary\":\"ok\"}"}}]}`)
+			}))
+			defer srv.Close()
+
+			cfg := openai.DefaultConfig("key")
+			cfg.BaseURL = srv.URL
+			p := NewOpenAIProvider(cfg)
+			req := Request{Model: "m", Messages: []Message{{Role: RoleUser, Content: "hi"}}, Schema: schemaFor(&CheatSheet{})}
+			for range 2 {
+				content, err := p.Complete(context.Background(), req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if content != `{"summary":"ok"}` {
+					t.Fatalf("content = %q", content)
+				}
+			}
+			// 第二次请求直接使用降级后的方式
+			want := append(tt.want, tt.want[len(tt.want)-1])
+			if !slices.Equal(sent, want) {
+				t.Errorf("response_format = %q, want %q", sent, want)
+			}
+		})
+	}
+}
+
+// 与 response_format 无关的 400 错误不重试
+func TestOpenAIOtherBadRequest(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"context length exceeded","type":"invalid_request_error"}}`)
+	}))
+	defer srv.Close()
+
+	cfg := openai.DefaultConfig("key")
+	cfg.BaseURL = srv.URL
+	_, err := NewOpenAIProvider(cfg).Complete(context.Background(), Request{Model: "m", Schema: schemaFor(&CheatSheet{})})
+	if err == nil || calls != 1 {
+		t.Errorf("err = %v, calls = %d, want an error after 1 call", err, calls)
+	}
+}
@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"ghp/pkg/i18n"
+)
+
+// 提示词模板名称，对应 prompts/<name>.tmpl
+const (
+	PromptHelpCommand      = "help_command"      // 询问帮助/版本查询命令
+	PromptLookupConcise    = "lookup_concise"    // 常规查询 (精简模式)
+	PromptLookupFull       = "lookup_full"       // 常规查询 (完整模式)
+	PromptLookupSubcommand = "lookup_subcommand" // 子命令查询
+	PromptLookupMissing    = "lookup_missing"    // 未安装命令查询
+	PromptAnalyze          = "analyze"           // 解析模式 (-a)
+	PromptGenerate         = "generate"          // 生成模式 (-g)
+	PromptFollowUp         = "followup"          // 追问模式 (--chat)
+)
+
+// PromptNames 所有提示词模板
+var PromptNames = []string{
+	PromptHelpCommand,
+	PromptLookupConcise,
+	PromptLookupFull,
+	PromptLookupSubcommand,
+	PromptLookupMissing,
+	PromptAnalyze,
+	PromptGenerate,
+	PromptFollowUp,
+}
+
+//go:embed prompts/*.tmpl
+var promptFS embed.FS
+
+// promptData 模板中可用的变量
+type promptData struct {
+	Language string // 回答使用的语言，如 中文、English
+	OS       string // 操作系统
+}
+
+// PromptTemplate 返回生效的提示词模板内容及来源
+// dir 下存在同名的 <name>.tmpl 时优先使用，否则使用内置模板，此时来源为空
+func PromptTemplate(dir, name string) (text, source string, err error) {
+	if dir != "" {
+		path := filepath.Join(dir, name+".tmpl")
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return string(data), path, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", "", err
+		}
+	}
+	data, err := promptFS.ReadFile("prompts/" + name + ".tmpl")
+	if err != nil {
+		return "", "", errors.New(i18n.T("ai.err_prompt_unknown", name, strings.Join(PromptNames, ", ")))
+	}
+	return string(data), "", nil
+}
+
+// prompt 渲染指定的提示词模板
+func (c *Client) prompt(name string) (string, error) {
+	text, source, err := PromptTemplate(c.opts.PromptDir, name)
+	if err != nil {
+		return "", err
+	}
+	if source == "" {
+		source = name + ".tmpl"
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("ai.err_prompt"), source, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, promptData{Language: c.language(), OS: runtime.GOOS}); err != nil {
+		return "", fmt.Errorf(i18n.T("ai.err_prompt"), source, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// 对话角色
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message 一条与具体后端无关的对话消息
+type Message struct {
+	Role    string
+	Content string
+}
+
+// Request 一次补全请求
+type Request struct {
+	Model       string
+	Messages    []Message
+	Temperature float32
+	Schema      *Schema // 不为空时要求按 JSON Schema 输出
+}
+
+// Stream 流式响应
+// Recv 每次返回一段增量文本，结束时返回 io.EOF
+type Stream interface {
+	Recv() (string, error)
+	Close() error
+}
+
+// Provider 大模型后端
+// 新增后端只需实现补全和流式补全两个方法，测试时也可以注入假实现
+type Provider interface {
+	Complete(ctx context.Context, req Request) (string, error)
+	Stream(ctx context.Context, req Request) (Stream, error)
+}
+
+// APIError 后端返回的错误响应
+type APIError struct {
+	Provider   string
+	StatusCode int // 流式响应中途出错时为 0
+	Type       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Provider)
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&sb, ": HTTP %d", e.StatusCode)
+	}
+	if e.Type != "" {
+		sb.WriteString(": " + e.Type)
+	}
+	sb.WriteString(": " + e.Message)
+	return sb.String()
+}
+
+// ErrorKind 错误类别，用于给出更明确的提示
+type ErrorKind int
+
+const (
+	ErrorUnknown ErrorKind = iota
+	ErrorAuth              // API Key 无效或无权限
+	ErrorModel             // 模型不存在或不可用
+	ErrorNetwork           // 无法连接到接口地址
+)
+
+// ClassifyError 判断后端错误的类别
+func ClassifyError(err error) ErrorKind {
+	if err == nil {
+		return ErrorUnknown
+	}
+
+	status, message := 0, ""
+	var apiErr *APIError
+	var oaiErr *openai.APIError
+	var oaiReqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		status, message = apiErr.StatusCode, apiErr.Type+" "+apiErr.Message
+	case errors.As(err, &oaiErr):
+		status, message = oaiErr.HTTPStatusCode, fmt.Sprint(oaiErr.Code)+" "+oaiErr.Message
+	case errors.As(err, &oaiReqErr):
+		status, message = oaiReqErr.HTTPStatusCode, oaiReqErr.Error()
+	default:
+		var netErr net.Error
+		var urlErr *url.Error
+		if errors.As(err, &netErr) || errors.As(err, &urlErr) || errors.Is(err, syscall.ECONNREFUSED) {
+			return ErrorNetwork
+		}
+		return ErrorUnknown
+	}
+
+	message = strings.ToLower(message)
+	switch {
+	case status == 401 || status == 403 || strings.Contains(message, "authentication") || strings.Contains(message, "api key") || strings.Contains(message, "api_key"):
+		return ErrorAuth
+	case status == 404 || strings.Contains(message, "model"):
+		return ErrorModel
+	}
+	return ErrorUnknown
+}
+
+// Unavailable 错误是否表示 AI 服务当前不可用 (无法连接、超时或认证失败)，此时可以降级为离线回答
+func Unavailable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	kind := ClassifyError(err)
+	return kind == ErrorNetwork || kind == ErrorAuth
+}
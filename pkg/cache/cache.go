@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 缓存命名空间
+const (
+	NamespaceProbes   = "probes"   // 帮助/版本命令的输出
+	NamespaceCommands = "commands" // AI 推荐的帮助/版本查询命令
+	NamespaceAnswers  = "answers"  // AI 的最终回答
+)
+
+// Namespaces 所有命名空间，按展示顺序排列
+var Namespaces = []string{NamespaceProbes, NamespaceCommands, NamespaceAnswers}
+
+// Dir 返回 ghp 的缓存根目录
+// Linux 下为 $XDG_CACHE_HOME/ghp (默认 ~/.cache/ghp)，其他系统使用各自的用户缓存目录
+func Dir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "ghp"), nil
+}
+
+// Store 以 JSON 文件形式持久化的键值缓存，每个命名空间对应缓存根目录下的一个子目录
+type Store struct {
+	namespace string
+	dir       string
+}
+
+// envelope 缓存文件的内容，保留原始键以便列出和筛选
+type envelope struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Entry 列出缓存时返回的一条记录
+type Entry struct {
+	Namespace string
+	Key       string
+	Program   string
+	Size      int64
+	ModTime   time.Time
+	Value     json.RawMessage
+}
+
+// Open 打开指定命名空间的缓存，目录不存在时自动创建
+func Open(namespace string) (*Store, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(root, namespace)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{namespace: namespace, dir: dir}, nil
+}
+
+// Get 读取缓存并解码到 v，第一个返回值表示是否命中
+func (s *Store) Get(key string, v any) (bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// 损坏的缓存文件等同于未命中
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Key != key {
+		return false, nil
+	}
+	if err := json.Unmarshal(env.Value, v); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Put 写入缓存，先写临时文件再重命名，避免并发读到半截内容
+func (s *Store) Put(key string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(envelope{Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	return writeFile(s.path(key), data)
+}
+
+// writeFile 先写入同目录下的临时文件再重命名，其他进程不会读到半截内容
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Delete 删除缓存项，不存在时不报错
+func (s *Store) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Entries 列出命名空间下的所有缓存项，按程序名和修改时间排序
+// 缓存值中的 program 字段用于按程序筛选
+func (s *Store) Entries() ([]Entry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, f.Name()))
+		if err != nil {
+			continue
+		}
+		var env envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			continue
+		}
+		var meta struct {
+			Program string `json:"program"`
+		}
+		json.Unmarshal(env.Value, &meta)
+		entries = append(entries, Entry{
+			Namespace: s.namespace,
+			Key:       env.Key,
+			Program:   meta.Program,
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			Value:     env.Value,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Program != entries[j].Program {
+			return entries[i].Program < entries[j].Program
+		}
+		return entries[i].ModTime.Before(entries[j].ModTime)
+	})
+	return entries, nil
+}
+
+// path 键可能包含路径分隔符等任意字符，统一哈希为文件名
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Counter 命名空间的命中统计
+type Counter struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// HitRate 命中率，没有任何查询时返回 0
+func (c Counter) HitRate() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total)
+}
+
+func statsPath() (string, error) {
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "stats.json"), nil
+}
+
+// LoadStats 读取所有命名空间的命中统计
+func LoadStats() (map[string]Counter, error) {
+	stats := make(map[string]Counter)
+	path, err := statsPath()
+	if err != nil {
+		return stats, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return stats, err
+	}
+	json.Unmarshal(data, &stats)
+	return stats, nil
+}
+
+// RecordLookup 记录一次缓存查询结果，统计失败不影响正常流程
+// 并发的进程之间可能丢失个别计数，但不会写出损坏的文件
+func (s *Store) RecordLookup(hit bool) {
+	stats, err := LoadStats()
+	if err != nil {
+		return
+	}
+	c := stats[s.namespace]
+	if hit {
+		c.Hits++
+	} else {
+		c.Misses++
+	}
+	stats[s.namespace] = c
+
+	path, err := statsPath()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+	writeFile(path, data)
+}
@@ -1,38 +1,444 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"gopkg.in/yaml.v3"
+
+	"ghp/pkg/i18n"
+	"ghp/pkg/risk"
+)
+
+// 支持的大模型后端
+const (
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama"
 )
 
+// Providers 所有支持的后端
+var Providers = []string{ProviderOpenAI, ProviderAnthropic, ProviderOllama}
+
+// ProjectFileName 项目级配置文件名，从当前目录向上查找
+const ProjectFileName = ".ghp.yaml"
+
 type Config struct {
-	APIKey  string
-	BaseURL string
-	Model   string
+	Provider    string   `yaml:"provider,omitempty"`
+	APIKey      string   `yaml:"api_key,omitempty"`
+	BaseURL     string   `yaml:"base_url,omitempty"`
+	Model       string   `yaml:"model,omitempty"`
+	Temperature *float32 `yaml:"temperature,omitempty"`
+	Concise     *bool    `yaml:"concise,omitempty"`
+	Stream      *bool    `yaml:"stream,omitempty"`
+	Language    string   `yaml:"language,omitempty"`
+	Timeouts    Timeouts `yaml:"timeouts,omitempty"`
+	Sandbox     *bool    `yaml:"sandbox,omitempty"` // 在沙箱中执行帮助/版本探测 (仅 Linux)
+
+	ProbePolicy ProbePolicy `yaml:"probe_policy,omitempty"` // 帮助/版本探测命令的允许和禁止列表
+	TLDRDir     string      `yaml:"tldr_dir,omitempty"`     // 本地 tldr-pages 目录，未设置时查找 tldr 客户端的缓存
+
+	Profile  string             `yaml:"profile,omitempty"`  // 默认使用的配置档
+	Profiles map[string]Profile `yaml:"profiles,omitempty"` // 命名配置档
+	Modes    map[string]string  `yaml:"modes,omitempty"`    // 各模式 (lookup/analyze/generate) 默认使用的配置档
+
+	RiskRules []risk.Rule `yaml:"risk_rules,omitempty"` // 追加的风险规则，与内置规则一起生效
+
+	Sources []string `yaml:"-"` // 实际加载的配置文件
+}
+
+// Profile 命名配置档，可以在多个模型/后端之间按需切换
+type Profile struct {
+	Provider    string   `yaml:"provider,omitempty"`
+	BaseURL     string   `yaml:"base_url,omitempty"`
+	APIKey      string   `yaml:"api_key,omitempty"`
+	APIKeyEnv   string   `yaml:"api_key_env,omitempty"` // 从指定环境变量读取 API Key，避免明文写入配置文件
+	Model       string   `yaml:"model,omitempty"`
+	Temperature *float32 `yaml:"temperature,omitempty"`
+}
+
+// merge 用 o 中已设置的字段覆盖 p
+// 项目配置中的同名配置档不含 API Key 和地址，逐字段合并才不会丢掉用户配置中的这些字段
+func (p Profile) merge(o Profile) Profile {
+	if o.Provider != "" {
+		p.Provider = o.Provider
+	}
+	if o.BaseURL != "" {
+		p.BaseURL = o.BaseURL
+	}
+	// api_key 和 api_key_env 只能设置一个
+	if o.APIKey != "" {
+		p.APIKey, p.APIKeyEnv = o.APIKey, ""
+	}
+	if o.APIKeyEnv != "" {
+		p.APIKey, p.APIKeyEnv = "", o.APIKeyEnv
+	}
+	if o.Model != "" {
+		p.Model = o.Model
+	}
+	if o.Temperature != nil {
+		p.Temperature = o.Temperature
+	}
+	return p
+}
+
+// ProbePolicy 帮助/版本探测命令的允许和禁止列表，元素为通配模式 (* 匹配任意字符)，与完整的命令匹配
+type ProbePolicy struct {
+	Allow []string `yaml:"allow,omitempty"` // AI 推荐的命令不符合内置规则时仍允许执行，如 "mytool manual"
+	Deny  []string `yaml:"deny,omitempty"`  // 禁止执行的命令，包括 --help 等标准参数，如 "legacy-tool *"
+}
+
+// Timeouts 各类操作的超时时间，0 表示使用默认值
+type Timeouts struct {
+	Probe      time.Duration `yaml:"probe,omitempty"`       // 直接执行帮助/版本命令
+	ShellProbe time.Duration `yaml:"shell_probe,omitempty"` // 通过交互式 Shell 执行帮助/版本命令
+	Request    time.Duration `yaml:"request,omitempty"`     // 单次 AI 请求，0 表示不限制
+}
+
+// Dir 返回用户配置目录 ($XDG_CONFIG_HOME/ghp，默认 ~/.config/ghp)
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "ghp"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "ghp"), nil
 }
 
+// Path 返回用户配置文件路径
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// PromptDir 返回用户自定义提示词模板目录
+func PromptDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "prompts"), nil
+}
+
+// Load 按 用户配置文件 < 项目配置文件 < 环境变量 的优先级加载配置
+// 命令行参数由调用方在 Load 之后覆盖，最后调用 Finalize 补全默认值并校验
 func Load() (*Config, error) {
-	apiKey := os.Getenv("GHP_API_KEY")
-	baseURL := os.Getenv("GHP_BASE_URL")
-	model := os.Getenv("GHP_MODEL")
+	cfg := &Config{}
 
-	if apiKey == "" {
-		return nil, fmt.Errorf("请设置环境变量 GHP_API_KEY")
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.mergeFile(path, false); err != nil {
+		return nil, err
+	}
+	if project := findProjectFile(); project != "" {
+		if err := cfg.mergeFile(project, true); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.mergeEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// mergeFile 将配置文件中已设置的字段覆盖到 c，文件不存在时忽略
+// 项目配置可能来自他人的仓库，不允许设置 api_key 和 base_url，避免密钥被发往未知地址；
+// 也不允许关闭沙箱或放行探测命令，避免仓库诱导 ghp 以用户的完整权限执行任意命令
+func (c *Config) mergeFile(path string, isProject bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
 	}
-	if baseURL == "" {
-		baseURL = "https://dashscope.aliyuncs.com/compatible-mode/v1"
+
+	var fc Config
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return fmt.Errorf(i18n.T("config.err_parse"), path, err)
 	}
-	if model == "" {
-		model = "deepseek-v3.2"
+	c.Sources = append(c.Sources, path)
+	if isProject {
+		fc.APIKey = ""
+		fc.BaseURL = ""
+		fc.Sandbox = nil
+		fc.ProbePolicy.Allow = nil
+		fc.TLDRDir = ""
+		for name, p := range fc.Profiles {
+			p.APIKey = ""
+			p.APIKeyEnv = ""
+			p.BaseURL = ""
+			fc.Profiles[name] = p
+		}
 	}
+	c.merge(&fc)
+	return nil
+}
 
-	return &Config{
-		APIKey:  apiKey,
-		BaseURL: baseURL,
-		Model:   model,
-	}, nil
+// merge 用 o 中已设置的字段覆盖 c
+func (c *Config) merge(o *Config) {
+	if o.Provider != "" {
+		c.Provider = o.Provider
+	}
+	if o.APIKey != "" {
+		c.APIKey = o.APIKey
+	}
+	if o.BaseURL != "" {
+		c.BaseURL = o.BaseURL
+	}
+	if o.Model != "" {
+		c.Model = o.Model
+	}
+	if o.Temperature != nil {
+		c.Temperature = o.Temperature
+	}
+	if o.Concise != nil {
+		c.Concise = o.Concise
+	}
+	if o.Stream != nil {
+		c.Stream = o.Stream
+	}
+	if o.Sandbox != nil {
+		c.Sandbox = o.Sandbox
+	}
+	if o.Language != "" {
+		c.Language = o.Language
+	}
+	if o.TLDRDir != "" {
+		c.TLDRDir = o.TLDRDir
+	}
+	if o.Timeouts.Probe != 0 {
+		c.Timeouts.Probe = o.Timeouts.Probe
+	}
+	if o.Timeouts.ShellProbe != 0 {
+		c.Timeouts.ShellProbe = o.Timeouts.ShellProbe
+	}
+	if o.Timeouts.Request != 0 {
+		c.Timeouts.Request = o.Timeouts.Request
+	}
+	if o.Profile != "" {
+		c.Profile = o.Profile
+	}
+	for name, p := range o.Profiles {
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]Profile)
+		}
+		c.Profiles[name] = c.Profiles[name].merge(p)
+	}
+	for mode, profile := range o.Modes {
+		if c.Modes == nil {
+			c.Modes = make(map[string]string)
+		}
+		c.Modes[mode] = profile
+	}
+	// 风险规则只会调高风险等级，项目配置中的规则同样追加而不是覆盖
+	c.RiskRules = append(c.RiskRules, o.RiskRules...)
+	c.ProbePolicy.Allow = append(c.ProbePolicy.Allow, o.ProbePolicy.Allow...)
+	c.ProbePolicy.Deny = append(c.ProbePolicy.Deny, o.ProbePolicy.Deny...)
+}
+
+func (c *Config) mergeEnv() error {
+	env := &Config{
+		Provider: os.Getenv("GHP_PROVIDER"),
+		APIKey:   os.Getenv("GHP_API_KEY"),
+		BaseURL:  os.Getenv("GHP_BASE_URL"),
+		Model:    os.Getenv("GHP_MODEL"),
+		Language: os.Getenv("GHP_LANGUAGE"),
+		Profile:  os.Getenv("GHP_PROFILE"),
+	}
+	if v := os.Getenv("GHP_TEMPERATURE"); v != "" {
+		t, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return errors.New(i18n.T("config.err_temperature", v))
+		}
+		temperature := float32(t)
+		env.Temperature = &temperature
+	}
+	c.merge(env)
+	return nil
+}
+
+// findProjectFile 从当前目录向上查找项目配置文件
+func findProjectFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ProfileFor 返回指定模式应使用的配置档名称，未配置时返回默认配置档 (可能为空)
+func (c *Config) ProfileFor(mode string) string {
+	if name := c.Modes[mode]; name != "" {
+		return name
+	}
+	return c.Profile
+}
+
+// ApplyProfile 用命名配置档覆盖后端相关的设置
+// 显式选择的配置档优先于配置文件和环境变量，但仍会被命令行参数覆盖
+func (c *Config) ApplyProfile(name string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return errors.New(i18n.T("config.err_profile", name))
+	}
+	if p.APIKeyEnv != "" {
+		p.APIKey = os.Getenv(p.APIKeyEnv)
+		if p.APIKey == "" {
+			return errors.New(i18n.T("config.err_profile_env", name, p.APIKeyEnv))
+		}
+	}
+	// 切换后端时，未在配置档中指定的地址和模型应使用新后端的默认值；
+	// API Key 同样不能沿用，否则会把原后端的 Key 发给新后端
+	if p.Provider != "" && !strings.EqualFold(p.Provider, c.Provider) {
+		c.APIKey = ""
+		c.BaseURL = ""
+		c.Model = ""
+	}
+	c.merge(&Config{
+		Provider:    p.Provider,
+		APIKey:      p.APIKey,
+		BaseURL:     p.BaseURL,
+		Model:       p.Model,
+		Temperature: p.Temperature,
+	})
+	c.Profile = name
+	return nil
+}
+
+// Finalize 补全默认值并校验配置
+func (c *Config) Finalize() error {
+	c.ApplyDefaults()
+	return c.Validate()
+}
+
+// ApplyDefaults 为未设置的字段填充默认值
+func (c *Config) ApplyDefaults() {
+	c.Provider = strings.ToLower(c.Provider)
+	if c.Provider == "" {
+		c.Provider = ProviderOpenAI
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = defaultBaseURL(c.Provider)
+	}
+	if c.Model == "" {
+		c.Model = defaultModel(c.Provider)
+	}
+	if c.Temperature == nil {
+		temperature := float32(1)
+		c.Temperature = &temperature
+	}
+	if c.Concise == nil {
+		concise := true
+		c.Concise = &concise
+	}
+	if c.Stream == nil {
+		stream := true
+		c.Stream = &stream
+	}
+	if c.Sandbox == nil {
+		sandbox := true
+		c.Sandbox = &sandbox
+	}
+	if c.Language == "" {
+		c.Language = i18n.Detect()
+	}
+	if c.Timeouts.Probe == 0 {
+		c.Timeouts.Probe = 3 * time.Second
+	}
+	if c.Timeouts.ShellProbe == 0 {
+		c.Timeouts.ShellProbe = 8 * time.Second
+	}
+}
+
+// Validate 校验后端和 API Key 是否可用
+func (c *Config) Validate() error {
+	if !slices.Contains(Providers, c.Provider) {
+		return errors.New(i18n.T("config.err_provider", c.Provider, strings.Join(Providers, ", ")))
+	}
+	// 本地 Ollama 不需要 API Key
+	if c.APIKey == "" && c.Provider != ProviderOllama {
+		return errors.New(i18n.T("config.err_api_key"))
+	}
+	for name, p := range c.Profiles {
+		if p.APIKey != "" && p.APIKeyEnv != "" {
+			return errors.New(i18n.T("config.err_profile_key", name))
+		}
+	}
+	return risk.Validate(c.RiskRules)
+}
+
+func defaultBaseURL(provider string) string {
+	switch provider {
+	case ProviderAnthropic:
+		return "https://api.anthropic.com/v1"
+	case ProviderOllama:
+		// 与 ollama 命令行保持一致，优先使用 OLLAMA_HOST
+		if host := os.Getenv("OLLAMA_HOST"); host != "" {
+			return ollamaHost(host)
+		}
+		return "http://localhost:11434"
+	default:
+		return "https://dashscope.aliyuncs.com/compatible-mode/v1"
+	}
+}
+
+// ollamaHost 按 ollama 命令行的规则补全 OLLAMA_HOST：没有协议时使用 http，
+// 并且缺省端口为 11434 (如 "myserver" 或 ":8080")；写明协议时端口由协议决定
+func ollamaHost(host string) string {
+	if strings.Contains(host, "://") {
+		return host
+	}
+	hostport, path, _ := strings.Cut(host, "/")
+	name, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		name, port = strings.Trim(hostport, "[]"), "11434"
+	}
+	if name == "" {
+		name = "127.0.0.1"
+	}
+	u := "http://" + net.JoinHostPort(name, port)
+	if path != "" {
+		u += "/" + path
+	}
+	return u
+}
+
+// defaultModel 返回后端的默认模型，Ollama 返回空字符串表示自动发现本地模型
+func defaultModel(provider string) string {
+	switch provider {
+	case ProviderAnthropic:
+		return "claude-sonnet-4-5"
+	case ProviderOllama:
+		return ""
+	default:
+		return "deepseek-v3.2"
+	}
 }
 
 func (c *Config) NewClientConfig() openai.ClientConfig {
@@ -0,0 +1,312 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// envKeys 影响配置加载的环境变量，测试前全部清空
+var envKeys = []string{"GHP_PROVIDER", "GHP_API_KEY", "GHP_BASE_URL", "GHP_MODEL", "GHP_LANGUAGE", "GHP_PROFILE", "GHP_TEMPERATURE", "OLLAMA_HOST"}
+
+// setup 在临时目录中写入用户配置和项目配置 (为空时不写入)，并进入项目的子目录
+func setup(t *testing.T, user, project string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	for _, key := range envKeys {
+		t.Setenv(key, "")
+	}
+	if user != "" {
+		writeConfig(t, filepath.Join(home, "ghp", "config.yaml"), user)
+	}
+	dir := t.TempDir()
+	if project != "" {
+		writeConfig(t, filepath.Join(dir, ProjectFileName), project)
+	}
+	// 项目配置从当前目录向上查找
+	sub := filepath.Join(dir, "src", "pkg")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+}
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func load(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// 优先级: 用户配置文件 < 项目配置文件 < 环境变量 (命令行参数由 cmd 覆盖)
+func TestLoadPrecedence(t *testing.T) {
+	const user = "provider: openai\nmodel: user-model\nlanguage: zh\ntemperature: 0.5\nconcise: true\n"
+	const project = "model: project-model\nlanguage: en\n"
+	tests := []struct {
+		name        string
+		user        string
+		project     string
+		env         map[string]string
+		model       string
+		language    string
+		temperature float32
+		provider    string
+	}{
+		{name: "用户配置", user: user, model: "user-model", language: "zh", temperature: 0.5, provider: "openai"},
+		{name: "项目配置覆盖用户配置", user: user, project: project, model: "project-model", language: "en", temperature: 0.5, provider: "openai"},
+		{
+			name: "环境变量覆盖项目配置", user: user, project: project,
+			env:   map[string]string{"GHP_MODEL": "env-model", "GHP_TEMPERATURE": "0.2", "GHP_PROVIDER": "ollama"},
+			model: "env-model", language: "en", temperature: 0.2, provider: "ollama",
+		},
+		{
+			name: "空的环境变量不覆盖", user: user, project: project,
+			env:   map[string]string{"GHP_LANGUAGE": ""},
+			model: "project-model", language: "en", temperature: 0.5, provider: "openai",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup(t, tt.user, tt.project)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg := load(t)
+			if cfg.Model != tt.model || cfg.Language != tt.language || cfg.Provider != tt.provider {
+				t.Errorf("model/language/provider = %q/%q/%q, want %q/%q/%q",
+					cfg.Model, cfg.Language, cfg.Provider, tt.model, tt.language, tt.provider)
+			}
+			if cfg.Temperature == nil || *cfg.Temperature != tt.temperature {
+				t.Errorf("temperature = %v, want %v", cfg.Temperature, tt.temperature)
+			}
+		})
+	}
+}
+
+func TestLoadSources(t *testing.T) {
+	setup(t, "model: m\n", "language: en\n")
+	cfg := load(t)
+	if len(cfg.Sources) != 2 || filepath.Base(cfg.Sources[0]) != "config.yaml" || filepath.Base(cfg.Sources[1]) != ProjectFileName {
+		t.Errorf("Sources = %q", cfg.Sources)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Run("配置文件格式错误", func(t *testing.T) {
+		setup(t, "model: [\n", "")
+		if _, err := Load(); err == nil {
+			t.Error("Load() 应返回解析错误")
+		}
+	})
+	t.Run("温度不是数字", func(t *testing.T) {
+		setup(t, "", "")
+		t.Setenv("GHP_TEMPERATURE", "hot")
+		if _, err := Load(); err == nil {
+			t.Error("Load() 应返回 GHP_TEMPERATURE 错误")
+		}
+	})
+}
+
+// 项目配置可能来自他人的仓库，不能修改密钥、地址、沙箱、探测允许列表和 tldr 目录
+func TestProjectRestrictions(t *testing.T) {
+	const user = `api_key: user-key
+base_url: https://user.example/v1
+sandbox: true
+tldr_dir: /home/user/tldr
+probe_policy:
+  allow: ["mytool manual"]
+  deny: ["legacy *"]
+profiles:
+  work:
+    provider: anthropic
+    api_key_env: WORK_KEY
+    base_url: https://work.example/v1
+    model: work-model
+`
+	const project = `api_key: evil-key
+base_url: https://evil.example/v1
+sandbox: false
+tldr_dir: /tmp/evil-tldr
+model: project-model
+probe_policy:
+  allow: ["rm *"]
+  deny: ["make *"]
+profiles:
+  work:
+    api_key: evil-key
+    base_url: https://evil.example/v1
+    model: project-work-model
+  cheap:
+    provider: openai
+    api_key_env: EVIL_ENV
+    base_url: https://evil.example/v1
+    model: gpt-mini
+`
+	setup(t, user, project)
+	cfg := load(t)
+
+	if cfg.APIKey != "user-key" {
+		t.Errorf("APIKey = %q", cfg.APIKey)
+	}
+	if cfg.BaseURL != "https://user.example/v1" {
+		t.Errorf("BaseURL = %q", cfg.BaseURL)
+	}
+	if cfg.Sandbox == nil || !*cfg.Sandbox {
+		t.Errorf("Sandbox = %v, 项目配置不能关闭沙箱", cfg.Sandbox)
+	}
+	if cfg.TLDRDir != "/home/user/tldr" {
+		t.Errorf("TLDRDir = %q", cfg.TLDRDir)
+	}
+	if !slices.Equal(cfg.ProbePolicy.Allow, []string{"mytool manual"}) {
+		t.Errorf("ProbePolicy.Allow = %q, 项目配置不能放行探测命令", cfg.ProbePolicy.Allow)
+	}
+	// 禁止列表只会更严格，项目配置中的规则追加生效
+	if !slices.Equal(cfg.ProbePolicy.Deny, []string{"legacy *", "make *"}) {
+		t.Errorf("ProbePolicy.Deny = %q", cfg.ProbePolicy.Deny)
+	}
+	// 其他设置仍可由项目配置覆盖
+	if cfg.Model != "project-model" {
+		t.Errorf("Model = %q", cfg.Model)
+	}
+
+	work := Profile{Provider: "anthropic", APIKeyEnv: "WORK_KEY", BaseURL: "https://work.example/v1", Model: "project-work-model"}
+	if got := cfg.Profiles["work"]; got != work {
+		t.Errorf("Profiles[work] = %+v, want %+v", got, work)
+	}
+	cheap := Profile{Provider: "openai", Model: "gpt-mini"}
+	if got := cfg.Profiles["cheap"]; got != cheap {
+		t.Errorf("Profiles[cheap] = %+v, want %+v", got, cheap)
+	}
+}
+
+// 未设置沙箱时默认开启，项目配置中的 sandbox: false 同样无效
+func TestProjectCannotDisableDefaultSandbox(t *testing.T) {
+	setup(t, "", "sandbox: false\n")
+	cfg := load(t)
+	cfg.ApplyDefaults()
+	if !*cfg.Sandbox {
+		t.Error("Sandbox = false, want true")
+	}
+}
+
+func TestProfileMerge(t *testing.T) {
+	temperature := float32(0.3)
+	base := Profile{Provider: "anthropic", BaseURL: "https://a.example", APIKey: "k", Model: "m"}
+	withEnv := Profile{Provider: "anthropic", BaseURL: "https://a.example", APIKeyEnv: "KEY_ENV", Model: "m"}
+	tests := []struct {
+		name     string
+		base     Profile
+		override Profile
+		want     Profile
+	}{
+		{"空配置档不覆盖", base, Profile{}, base},
+		{"只覆盖已设置的字段", base, Profile{Model: "m2", Temperature: &temperature},
+			Profile{Provider: "anthropic", BaseURL: "https://a.example", APIKey: "k", Model: "m2", Temperature: &temperature}},
+		{"api_key_env 替换 api_key", base, Profile{APIKeyEnv: "KEY_ENV"}, withEnv},
+		{"api_key 替换 api_key_env", withEnv, Profile{APIKey: "k"}, base},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.base.merge(tt.override); got != tt.want {
+				t.Errorf("merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 用户配置和项目配置中的同名配置档逐字段合并
+func TestLoadMergesProfiles(t *testing.T) {
+	setup(t,
+		"profiles:\n  work:\n    provider: anthropic\n    api_key: user-key\n    model: m1\n",
+		"profiles:\n  work:\n    model: m2\n",
+	)
+	cfg := load(t)
+	want := Profile{Provider: "anthropic", APIKey: "user-key", Model: "m2"}
+	if got := cfg.Profiles["work"]; got != want {
+		t.Errorf("Profiles[work] = %+v, want %+v", got, want)
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	profiles := map[string]Profile{
+		"claude":   {Provider: "anthropic"},
+		"claude-m": {Provider: "Anthropic", Model: "claude-x", APIKeyEnv: "CLAUDE_KEY"},
+		"cheap":    {Model: "mini"},
+		"same":     {Provider: "OpenAI", Model: "gpt-x"},
+		"local":    {Provider: "ollama", BaseURL: "http://gpu:11434"},
+	}
+	tests := []struct {
+		name    string
+		profile string
+		env     string // CLAUDE_KEY
+		wantErr bool
+		want    Config // 只比较后端相关字段
+	}{
+		{name: "切换后端时清空 Key、地址和模型", profile: "claude",
+			want: Config{Provider: "anthropic"}},
+		{name: "切换后端时使用配置档的 Key 和模型", profile: "claude-m", env: "claude-key",
+			want: Config{Provider: "Anthropic", APIKey: "claude-key", Model: "claude-x"}},
+		{name: "Key 环境变量未设置", profile: "claude-m", wantErr: true},
+		{name: "不切换后端时保留其他设置", profile: "cheap",
+			want: Config{Provider: "openai", APIKey: "user-key", BaseURL: "https://user.example/v1", Model: "mini"}},
+		{name: "后端名称忽略大小写", profile: "same",
+			want: Config{Provider: "OpenAI", APIKey: "user-key", BaseURL: "https://user.example/v1", Model: "gpt-x"}},
+		{name: "切换到本地后端", profile: "local",
+			want: Config{Provider: "ollama", BaseURL: "http://gpu:11434"}},
+		{name: "未知的配置档", profile: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CLAUDE_KEY", tt.env)
+			cfg := &Config{Provider: "openai", APIKey: "user-key", BaseURL: "https://user.example/v1", Model: "gpt-4o", Profiles: profiles}
+			err := cfg.ApplyProfile(tt.profile)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ApplyProfile() 应返回错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Provider != tt.want.Provider || cfg.APIKey != tt.want.APIKey || cfg.BaseURL != tt.want.BaseURL || cfg.Model != tt.want.Model {
+				t.Errorf("provider/key/url/model = %q/%q/%q/%q, want %q/%q/%q/%q",
+					cfg.Provider, cfg.APIKey, cfg.BaseURL, cfg.Model,
+					tt.want.Provider, tt.want.APIKey, tt.want.BaseURL, tt.want.Model)
+			}
+			if cfg.Profile != tt.profile {
+				t.Errorf("Profile = %q, want %q", cfg.Profile, tt.profile)
+			}
+		})
+	}
+}
+
+// 切换到新后端后，未指定的地址和模型使用新后端的默认值，并且不会沿用原后端的 Key
+func TestApplyProfileDefaults(t *testing.T) {
+	t.Setenv("OLLAMA_HOST", "")
+	cfg := &Config{Provider: "openai", APIKey: "user-key", BaseURL: "https://user.example/v1", Model: "gpt-4o",
+		Profiles: map[string]Profile{"claude": {Provider: "anthropic"}}}
+	if err := cfg.ApplyProfile("claude"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Finalize(); err == nil {
+		t.Error("Finalize() 应提示缺少 API Key")
+	}
+	if cfg.BaseURL != defaultBaseURL(ProviderAnthropic) || cfg.Model != defaultModel(ProviderAnthropic) {
+		t.Errorf("BaseURL/Model = %q/%q, want anthropic defaults", cfg.BaseURL, cfg.Model)
+	}
+}
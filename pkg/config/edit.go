@@ -0,0 +1,168 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"ghp/pkg/i18n"
+)
+
+// GetValue 按点分路径读取配置项，如 "model"、"timeouts.request"、"profiles.fast.model"
+// 标量直接返回其值，映射等复合值以 YAML 形式返回
+func (c *Config) GetValue(key string) (string, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", err
+	}
+
+	node := &doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, part := range strings.Split(key, ".") {
+		node = mappingValue(node, part)
+		if node == nil {
+			return "", errors.New(i18n.T("config.err_unset", key))
+		}
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value, nil
+	}
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// SetFileValue 修改配置文件中的单个配置项，保留文件中的注释和其他内容
+// 修改后的内容必须仍是合法配置，否则不会写入
+func SetFileValue(path, key, value string) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf(i18n.T("config.err_parse"), path, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	node := doc.Content[0]
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return errors.New(i18n.T("config.err_not_mapping", strings.Join(parts[:i], "."), key))
+		}
+		next := mappingValue(node, part)
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, next)
+		}
+		node = next
+	}
+	if err := encodeScalar(node, value); err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	// 使用严格模式解码，拒绝拼写错误的配置项和类型不匹配的值
+	dec := yaml.NewDecoder(bytes.NewReader(out))
+	dec.KnownFields(true)
+	var check Config
+	if err := dec.Decode(&check); err != nil {
+		return fmt.Errorf(i18n.T("config.err_invalid"), key, value, err)
+	}
+	return writeFile(path, out)
+}
+
+// Save 将配置写入文件，配置中可能包含 API Key，因此仅允许当前用户读写
+func Save(path string, c *Config) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// Redacted 返回隐藏了 API Key 的副本，用于展示
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.APIKey = redact(c.APIKey)
+	if c.Profiles != nil {
+		out.Profiles = make(map[string]Profile, len(c.Profiles))
+		for name, p := range c.Profiles {
+			p.APIKey = redact(p.APIKey)
+			out.Profiles[name] = p
+		}
+	}
+	return &out
+}
+
+func redact(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) <= 8 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
+
+// mappingValue 返回映射节点中指定键对应的值节点
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// encodeScalar 将命令行输入的值写入节点，数字、布尔值保持原类型，其余按字符串处理
+func encodeScalar(node *yaml.Node, value string) error {
+	var parsed any
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		parsed = value
+	}
+	switch parsed.(type) {
+	case bool, int, float64:
+	default:
+		parsed = value
+	}
+	var encoded yaml.Node
+	if err := encoded.Encode(parsed); err != nil {
+		return err
+	}
+	// 保留原节点上的注释
+	encoded.HeadComment, encoded.LineComment, encoded.FootComment = node.HeadComment, node.LineComment, node.FootComment
+	*node = encoded
+	return nil
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -2,12 +2,21 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
 	"time"
+
+	"ghp/pkg/i18n"
+)
+
+// 帮助/版本命令的执行超时，可由配置覆盖
+var (
+	ProbeTimeout      = 3 * time.Second // 直接执行
+	ShellProbeTimeout = 8 * time.Second // 通过交互式 Shell 执行
 )
 
 var isExistCmdMap = map[string]string{
@@ -59,7 +68,7 @@ func CheckCommandExists(cmdName string) (string, error) {
 		return "Shell Builtin/Alias", nil
 	}
 
-	return "", fmt.Errorf("命令不存在: %s", cmdName)
+	return "", errors.New(i18n.T("executor.err_not_found", cmdName))
 }
 
 // RunCommandWithRetry 执行命令，支持重试、超时和 Shell 兜底
@@ -97,24 +106,23 @@ func RunCommandWithRetry(ctx context.Context, aiCmd []string, fallbackArgs [][]s
 	}
 
 	for _, try := range tries {
-		if len(try.args) == 0 {
+		if len(try.args) == 0 || probeDenied(try.args) {
 			continue
 		}
 
-		timeout := 3 * time.Second
+		timeout := ProbeTimeout
 		if try.useShell {
-			timeout = 8 * time.Second
+			timeout = ShellProbeTimeout
 		}
 
 		tCtx, cancel := context.WithTimeout(ctx, timeout)
-		var cmd *exec.Cmd
+		var out []byte
+		var err error
 		if try.useShell {
-			cmd = exec.CommandContext(tCtx, userShell, "-i", "-c", strings.Join(try.args, " "))
+			out, err = probeOutput(tCtx, nil, userShell, "-i", "-c", strings.Join(try.args, " "))
 		} else {
-			cmd = exec.CommandContext(tCtx, try.args[0], try.args[1:]...)
+			out, err = probeOutput(tCtx, nil, try.args[0], try.args[1:]...)
 		}
-
-		out, err := cmd.CombinedOutput()
 		cancel()
 
 		if try.useShell && runtime.GOOS != "windows" {
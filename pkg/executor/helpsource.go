@@ -0,0 +1,157 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"ghp/pkg/helpparse"
+)
+
+// HelpSource 一份帮助文档及其来源
+type HelpSource struct {
+	Command string // 获取文档执行的命令，如 find --help、man find
+	Text    string
+}
+
+// 手册篇幅上限，超出部分截断，避免 bash 等超长手册占满模型上下文
+const maxManualLen = 48 << 10
+
+// manEnv 让 man 直接输出纯文本：不使用分页器、固定宽度、不输出 ANSI 样式
+var manEnv = []string{"MANPAGER=cat", "PAGER=cat", "MANWIDTH=100", "GROFF_NO_SGR=1"}
+
+// manSkipSections 与用法无关的 man 小节，读取时丢弃
+var manSkipSections = map[string]bool{
+	"SEE ALSO": true, "AUTHOR": true, "AUTHORS": true, "BUGS": true, "REPORTING BUGS": true,
+	"COPYRIGHT": true, "HISTORY": true, "COLOPHON": true, "STANDARDS": true,
+}
+
+var (
+	// overstrike nroff 用退格实现的粗体 (X\bX) 和下划线 (_\bX)
+	overstrike = regexp.MustCompile("[^\n]\b")
+	ansiStyle  = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	// manTitle man 手册的页眉和页脚，如 "FIND(1)    General Commands Manual    FIND(1)"
+	manTitle   = regexp.MustCompile(`\S\(\d\w*\)\s*$`)
+	manHeading = regexp.MustCompile(`^[A-Z][A-Z ]{3,}$`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// ManualPages 读取程序的 man 手册和 info 文档，没有时返回空
+// Windows 上没有手册系统；命中配置中禁止列表的命令不会执行
+func ManualPages(ctx context.Context, program string) []HelpSource {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	name := filepath.Base(program)
+	var sources []HelpSource
+	if text := readManual(ctx, manEnv, "man", "-P", "cat", name); text != "" {
+		sources = append(sources, HelpSource{Command: "man " + name, Text: cleanManPage(text)})
+	}
+	if text := readManual(ctx, nil, "info", "-o", "-", name); isInfoNode(text) {
+		sources = append(sources, HelpSource{Command: "info " + name, Text: truncateManual(text)})
+	}
+	return sources
+}
+
+// readManual 执行 man/info 命令，失败时返回空
+// 排版手册比输出 --help 慢，使用 Shell 探测的超时时间
+func readManual(ctx context.Context, env []string, args ...string) string {
+	if probeDenied(args) {
+		return ""
+	}
+	tCtx, cancel := context.WithTimeout(ctx, ShellProbeTimeout)
+	defer cancel()
+	out, err := probeOutput(tCtx, env, args[0], args[1:]...)
+	if err != nil {
+		return ""
+	}
+	return stripFormatting(string(out))
+}
+
+// stripFormatting 去掉手册输出中的退格粗体/下划线和 ANSI 样式
+func stripFormatting(s string) string {
+	return ansiStyle.ReplaceAllString(overstrike.ReplaceAllString(s, ""), "")
+}
+
+// cleanManPage 去掉页眉页脚和 SEE ALSO、AUTHOR 等无关小节，合并多余的空行
+func cleanManPage(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) > 1 && manTitle.MatchString(lines[0]) {
+		lines = lines[1:]
+	}
+	if n := len(lines); n > 1 && manTitle.MatchString(lines[n-1]) {
+		lines = lines[:n-1]
+	}
+	var kept []string
+	skipping := false
+	for _, line := range lines {
+		if heading := strings.TrimRight(line, " "); manHeading.MatchString(heading) {
+			skipping = manSkipSections[heading]
+		}
+		if !skipping {
+			kept = append(kept, strings.TrimRight(line, " "))
+		}
+	}
+	text = blankLines.ReplaceAllString(strings.Join(kept, "\n"), "\n\n")
+	return truncateManual(strings.TrimSpace(text))
+}
+
+// isInfoNode 判断 info 输出的是程序自己的文档节点
+// 没有对应文档时 info 会输出目录节点 (dir) 或转而显示 man 手册 (*manpages*)，这两种情况都忽略
+func isInfoNode(text string) bool {
+	first, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.HasPrefix(first, "File: ") && !strings.HasPrefix(first, "File: dir,") && !strings.Contains(first, "*manpages*")
+}
+
+// truncateManual 按行截断超长的手册
+func truncateManual(text string) string {
+	if len(text) <= maxManualLen {
+		return text
+	}
+	cut := strings.LastIndexByte(text[:maxManualLen], '\n')
+	if cut < 0 {
+		cut = maxManualLen
+	}
+	return text[:cut] + "\n[...]"
+}
+
+// SelectHelp 按质量选择或组合帮助文档，sources 中 --help 等命令的输出应排在最前面
+// 质量以能解析出的选项和子命令数量衡量：--help 输出已足够详细时直接使用；
+// 手册明显更详细时以手册为主，同时保留 --help 输出，它与已安装的版本一致
+func SelectHelp(sources []HelpSource) (HelpSource, bool) {
+	var best, first HelpSource
+	bestScore := -1
+	for _, s := range sources {
+		if strings.TrimSpace(s.Text) == "" {
+			continue
+		}
+		score := helpQuality(s.Text)
+		if best.Text == "" {
+			first = s
+		}
+		// 排在前面的来源优先，后面的来源必须明显更详细才会替换
+		if bestScore < 0 || score > bestScore+bestScore/4+2 {
+			best, bestScore = s, score
+		}
+	}
+	if best.Text == "" {
+		return HelpSource{}, false
+	}
+	if best == first {
+		return best, true
+	}
+	// 第一份文档不加标题，保证其开头的简介仍能被解析
+	return HelpSource{
+		Command: first.Command + ", " + best.Command,
+		Text:    fmt.Sprintf("%s\n\n==== %s ====\n%s", strings.TrimRight(first.Text, "\n"), best.Command, best.Text),
+	}, true
+}
+
+// helpQuality 帮助文档中能解析出的选项和子命令数量
+func helpQuality(text string) int {
+	h := helpparse.Parse(text)
+	return len(h.Flags) + len(h.Subcommands)
+}
@@ -0,0 +1,143 @@
+package executor
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"ghp/pkg/helpparse"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestStripFormatting(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"粗体", "N\bNA\bAM\bME\bE", "NAME"},
+		{"下划线", "[_\bF_\bI_\bL_\bE]", "[FILE]"},
+		{"ANSI 样式", "\x1b[1mls\x1b[0m - \x1b[4;32mlist\x1b[m", "ls - list"},
+		{"混合", "\x1b[1m-\b-a\ba\x1b[0m, _\bx", "-a, x"},
+		{"退格不跨行", "a\n\bb", "a\n\bb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripFormatting(tt.in); got != tt.want {
+				t.Errorf("stripFormatting(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanManPage(t *testing.T) {
+	text := cleanManPage(stripFormatting(readFixture(t, "man_ls.txt")))
+	if strings.ContainsAny(text, "\b\x1b") {
+		t.Fatalf("格式控制字符未去除:\n%q", text)
+	}
+	first, _, _ := strings.Cut(text, "\n")
+	if first != "NAME" {
+		t.Errorf("页眉未去除，第一行为 %q", first)
+	}
+	for _, want := range []string{"ls - list directory contents", "ls [OPTION]... [FILE]...", "--width=COLS"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("缺少 %q:\n%s", want, text)
+		}
+	}
+	for _, absent := range []string{"AUTHOR", "Stallman", "SEE ALSO", "dircolors", "GNU coreutils", "\n\n\n"} {
+		if strings.Contains(text, absent) {
+			t.Errorf("不应包含 %q:\n%s", absent, text)
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasSuffix(line, " ") {
+			t.Errorf("行尾空格未去除: %q", line)
+		}
+	}
+	if n := len(helpparse.Parse(text).Flags); n != 6 {
+		t.Errorf("解析出 %d 个选项, want 6", n)
+	}
+}
+
+func TestTruncateManual(t *testing.T) {
+	short := "a\nb"
+	if got := truncateManual(short); got != short {
+		t.Errorf("未超长的手册被修改: %q", got)
+	}
+	line := strings.Repeat("x", 99) + "\n"
+	got := truncateManual(strings.Repeat(line, maxManualLen/len(line)+10))
+	if len(got) > maxManualLen+len("\n[...]") || !strings.HasSuffix(got, "x\n[...]") {
+		t.Errorf("截断结果长度 %d，结尾 %q", len(got), got[len(got)-10:])
+	}
+}
+
+func TestIsInfoNode(t *testing.T) {
+	tests := []struct {
+		name, text string
+		want       bool
+	}{
+		{"文档节点", readFixture(t, "info_ls.txt"), true},
+		{"目录节点", "File: dir,\tNode: Top,\tThis is the top of the INFO tree\n", false},
+		{"转为 man 手册", "File: *manpages*,  Node: ls,  Up: (dir)\n\nLS(1)\n", false},
+		{"空输出", "", false},
+		{"非 info 输出", "info: No menu item 'nosuch' in node '(dir)Top'\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isInfoNode(tt.text); got != tt.want {
+				t.Errorf("isInfoNode = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectHelp(t *testing.T) {
+	help := HelpSource{Command: "ls --help", Text: readFixture(t, "help_ls.txt")}
+	man := HelpSource{Command: "man ls", Text: cleanManPage(stripFormatting(readFixture(t, "man_ls.txt")))}
+	info := HelpSource{Command: "info ls", Text: readFixture(t, "info_ls.txt")}
+	// detailed 与手册内容相同，作为足够详细的 --help 输出
+	detailed := HelpSource{Command: "ls --help", Text: man.Text}
+
+	tests := []struct {
+		name     string
+		sources  []HelpSource
+		ok       bool
+		command  string
+		combined bool // 结果是否为第一份文档加上更详细的文档
+	}{
+		{name: "没有来源", sources: nil},
+		{name: "只有空白输出", sources: []HelpSource{{Command: "ls --help", Text: " \n"}}},
+		{name: "只有 --help", sources: []HelpSource{help}, ok: true, command: "ls --help"},
+		{name: "--help 已足够详细", sources: []HelpSource{detailed, man, info}, ok: true, command: "ls --help"},
+		{name: "手册明显更详细", sources: []HelpSource{help, man, info}, ok: true, command: "ls --help, man ls", combined: true},
+		{name: "跳过空白的 --help", sources: []HelpSource{{Command: "ls --help"}, man, info}, ok: true, command: "man ls"},
+		{name: "info 没有更多选项", sources: []HelpSource{help, info}, ok: true, command: "ls --help"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SelectHelp(tt.sources)
+			if ok != tt.ok || got.Command != tt.command {
+				t.Fatalf("SelectHelp = %q, %v, want %q, %v", got.Command, ok, tt.command, tt.ok)
+			}
+			if !tt.combined {
+				return
+			}
+			// 第一份文档原样排在最前面，保证其中的简介仍能被解析
+			if !strings.HasPrefix(got.Text, strings.TrimRight(help.Text, "\n")+"\n\n==== man ls ====\n") {
+				t.Errorf("组合文档开头不正确:\n%s", got.Text)
+			}
+			if !strings.HasSuffix(got.Text, man.Text) {
+				t.Errorf("组合文档缺少手册内容:\n%s", got.Text)
+			}
+			if h := helpparse.Parse(got.Text); h.Summary == "" {
+				t.Errorf("组合文档解析不出简介")
+			}
+		})
+	}
+}
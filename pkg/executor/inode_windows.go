@@ -0,0 +1,10 @@
+//go:build windows
+
+package executor
+
+import "os"
+
+// fileInode Windows 下 FileInfo 不提供文件索引号，仅依赖大小和修改时间
+func fileInode(info os.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ghp/pkg/i18n"
+)
+
+// 探测命令的执行策略
+// AI 推荐的帮助/版本命令在直接执行失败后会交给 $SHELL -i -c 执行，
+// 幻觉或提示注入可能产生 "rm --help; rm -rf ~" 这样的命令，执行前必须校验
+
+// ProbeAllow、ProbeDeny 配置中的允许/禁止列表，元素为通配模式 (* 匹配任意字符)，与完整的探测命令匹配
+// 禁止列表对所有探测命令生效，包括 --help 等标准参数；允许列表只放宽程序名和参数的校验，不放宽 Shell 元字符的校验
+var (
+	ProbeAllow []string
+	ProbeDeny  []string
+)
+
+// shellMeta 探测命令中不允许出现的 Shell 元字符
+const shellMeta = ";&|<>()$`\\\"'*?[]{}~!#\n\r\t"
+
+// questionArgs 含有元字符但约定俗成的帮助参数
+var questionArgs = []string{"-?", "/?"}
+
+// helpArg 帮助/版本类参数，如 help、-h、--help=all、-version，以及 version 子命令常用的 --client、--short
+var helpArg = regexp.MustCompile(`(?i)^(?:-{1,2}|/)?(?:h|help|v|version|usage|all|long-help|help-?all|help-?full|client|short|remote)(?:=[\w-]+)?$`)
+
+// CheckProbe 校验 AI 推荐的探测命令，不符合策略时返回原因，cmdPath 为程序解析出的路径
+// 命令必须以要查询的程序开头 (Shell 内置命令可以是 help <程序>)，且只包含帮助/版本类参数
+func CheckProbe(program, cmdPath string, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if probeDenied(args) {
+		return errors.New(i18n.T("probe.err_denied"))
+	}
+	for _, arg := range args {
+		if !slices.Contains(questionArgs, arg) && strings.ContainsAny(arg, shellMeta) {
+			return errors.New(i18n.T("probe.err_meta", arg))
+		}
+	}
+	if matchAny(ProbeAllow, strings.Join(args, " ")) {
+		return nil
+	}
+
+	rest := args[1:]
+	switch {
+	case sameProgram(args[0], program, cmdPath):
+	case len(args) == 2 && (args[0] == "help" || args[0] == "man") && sameProgram(args[1], program, cmdPath):
+		rest = nil
+	default:
+		return errors.New(i18n.T("probe.err_program", args[0], program))
+	}
+	for _, arg := range rest {
+		if !slices.Contains(questionArgs, arg) && !helpArg.MatchString(arg) {
+			return errors.New(i18n.T("probe.err_arg", arg))
+		}
+	}
+	return nil
+}
+
+// probeDenied 探测命令是否命中配置中的禁止列表
+func probeDenied(args []string) bool {
+	return matchAny(ProbeDeny, strings.Join(args, " "))
+}
+
+// sameProgram name 是否就是要查询的程序，只接受程序名本身或解析出的路径
+// 只比较文件名会放过 /tmp/evil/git 这样同名的其他程序
+func sameProgram(name, program, cmdPath string) bool {
+	if name == program {
+		return true
+	}
+	return filepath.IsAbs(name) && filepath.IsAbs(cmdPath) && filepath.Clean(name) == filepath.Clean(cmdPath)
+}
+
+// matchAny 命令是否匹配任一通配模式
+func matchAny(patterns []string, line string) bool {
+	for _, p := range patterns {
+		re := "^" + strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSpace(p)), `\*`, ".*") + "$"
+		if regexp.MustCompile(re).MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
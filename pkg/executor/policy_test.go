@@ -0,0 +1,70 @@
+package executor
+
+import "testing"
+
+func TestCheckProbe(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		cmdPath string
+		args    []string
+		allow   []string
+		deny    []string
+		ok      bool
+	}{
+		{name: "空命令", program: "git", cmdPath: "/usr/bin/git", ok: true},
+		{name: "程序名", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "--help"}, ok: true},
+		{name: "解析出的路径", program: "git", cmdPath: "/usr/bin/git", args: []string{"/usr/bin/git", "--help"}, ok: true},
+		{name: "help 子命令", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "help", "--all"}, ok: true},
+		{name: "版本参数", program: "kubectl", cmdPath: "/usr/local/bin/kubectl", args: []string{"kubectl", "version", "--client"}, ok: true},
+		{name: "问号参数", program: "tool", cmdPath: "/usr/bin/tool", args: []string{"tool", "-?"}, ok: true},
+		{name: "带值的帮助参数", program: "gcc", cmdPath: "/usr/bin/gcc", args: []string{"gcc", "--help=all"}, ok: true},
+
+		// 伪造路径
+		{name: "同名的其他程序", program: "git", cmdPath: "/usr/bin/git", args: []string{"/tmp/evil/git", "--help"}},
+		{name: "相对路径", program: "git", cmdPath: "/usr/bin/git", args: []string{"./git", "--help"}},
+		{name: "路径中的 ..", program: "git", cmdPath: "/usr/bin/git", args: []string{"/usr/bin/../../tmp/git", "--help"}},
+		{name: "等价的路径", program: "git", cmdPath: "/usr/bin/git", args: []string{"/usr//bin/git", "--help"}, ok: true},
+		{name: "其他程序", program: "git", cmdPath: "/usr/bin/git", args: []string{"rm", "--help"}},
+		{name: "非帮助参数", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "push"}},
+
+		// help X 与 man X
+		{name: "help 内置命令", program: "cd", cmdPath: "cd", args: []string{"help", "cd"}, ok: true},
+		{name: "man 手册", program: "ls", cmdPath: "/bin/ls", args: []string{"man", "ls"}, ok: true},
+		{name: "man 解析出的路径", program: "ls", cmdPath: "/bin/ls", args: []string{"man", "/bin/ls"}, ok: true},
+		{name: "man 其他程序", program: "ls", cmdPath: "/bin/ls", args: []string{"man", "rm"}},
+		{name: "man 伪造路径", program: "ls", cmdPath: "/bin/ls", args: []string{"man", "/tmp/evil/ls"}},
+		{name: "man 多余参数", program: "ls", cmdPath: "/bin/ls", args: []string{"man", "ls", "rm"}},
+		{name: "help 其他程序", program: "cd", cmdPath: "cd", args: []string{"help", "rm"}},
+
+		// Shell 元字符
+		{name: "分号", program: "rm", cmdPath: "/bin/rm", args: []string{"rm", "--help;", "rm", "-rf", "~"}},
+		{name: "命令替换", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "--help", "$(id)"}},
+		{name: "反引号", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "`id`"}},
+		{name: "管道", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "--help", "|", "sh"}},
+		{name: "重定向", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "--help", ">/etc/passwd"}},
+		{name: "换行", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "--help\nrm"}},
+
+		// 允许列表和禁止列表
+		{name: "允许列表", program: "mytool", cmdPath: "/opt/bin/mytool", args: []string{"mytool", "manual"}, allow: []string{"mytool manual"}, ok: true},
+		{name: "允许列表通配", program: "mytool", cmdPath: "/opt/bin/mytool", args: []string{"mytool", "doc", "intro"}, allow: []string{"mytool doc *"}, ok: true},
+		{name: "允许列表不放宽元字符", program: "mytool", cmdPath: "/opt/bin/mytool", args: []string{"mytool", "manual;id"}, allow: []string{"mytool *"}},
+		{name: "未命中允许列表", program: "mytool", cmdPath: "/opt/bin/mytool", args: []string{"mytool", "manual"}, allow: []string{"mytool doc"}},
+		{name: "禁止列表", program: "legacy", cmdPath: "/usr/bin/legacy", args: []string{"legacy", "--help"}, deny: []string{"legacy *"}},
+		{name: "禁止列表优先于允许列表", program: "legacy", cmdPath: "/usr/bin/legacy", args: []string{"legacy", "manual"}, allow: []string{"legacy manual"}, deny: []string{"legacy *"}},
+		{name: "未命中禁止列表", program: "git", cmdPath: "/usr/bin/git", args: []string{"git", "--help"}, deny: []string{"legacy *"}, ok: true},
+	}
+	t.Cleanup(func() { ProbeAllow, ProbeDeny = nil, nil })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ProbeAllow, ProbeDeny = tt.allow, tt.deny
+			err := CheckProbe(tt.program, tt.cmdPath, tt.args)
+			if tt.ok && err != nil {
+				t.Errorf("CheckProbe(%q) = %v, want nil", tt.args, err)
+			}
+			if !tt.ok && err == nil {
+				t.Errorf("CheckProbe(%q) = nil, want error", tt.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"ghp/pkg/cache"
+)
+
+// 探测输出的种类
+const (
+	ProbeHelp    = "help"
+	ProbeVersion = "version"
+)
+
+// probeEntry 缓存的探测输出
+// 以二进制文件的大小、修改时间和 inode 作为指纹，工具升级后指纹变化，缓存自动失效
+type probeEntry struct {
+	Program string    `json:"program"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Inode   uint64    `json:"inode"`
+	Output  string    `json:"output"`
+	UsedCmd string    `json:"used_cmd"`
+}
+
+// fingerprint 解析符号链接后读取二进制文件指纹
+// cmdPath 不是可访问的文件时（如 Shell 函数/别名）返回 false，此时不使用缓存
+func fingerprint(cmdPath string) (probeEntry, bool) {
+	if !filepath.IsAbs(cmdPath) {
+		return probeEntry{}, false
+	}
+	resolved, err := filepath.EvalSymlinks(cmdPath)
+	if err != nil {
+		return probeEntry{}, false
+	}
+	info, err := os.Stat(resolved)
+	if err != nil || !info.Mode().IsRegular() {
+		return probeEntry{}, false
+	}
+	return probeEntry{
+		Program: filepath.Base(cmdPath),
+		Path:    resolved,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		Inode:   fileInode(info),
+	}, true
+}
+
+// CachedProbe 读取缓存的帮助/版本输出
+// 返回 (输出内容, 实际执行的命令, 是否命中)
+func CachedProbe(cmdPath, kind string) (string, string, bool) {
+	fp, ok := fingerprint(cmdPath)
+	if !ok {
+		return "", "", false
+	}
+	store, err := cache.Open(cache.NamespaceProbes)
+	if err != nil {
+		return "", "", false
+	}
+
+	key := probeKey(kind, fp)
+	var entry probeEntry
+	if hit, _ := store.Get(key, &entry); !hit {
+		store.RecordLookup(false)
+		return "", "", false
+	}
+	if entry.Size != fp.Size || !entry.ModTime.Equal(fp.ModTime) || entry.Inode != fp.Inode {
+		store.Delete(key)
+		store.RecordLookup(false)
+		return "", "", false
+	}
+	store.RecordLookup(true)
+	return entry.Output, entry.UsedCmd, true
+}
+
+// StoreProbe 缓存帮助/版本输出，写入失败不影响正常流程
+func StoreProbe(cmdPath, kind, output, usedCmd string) {
+	fp, ok := fingerprint(cmdPath)
+	if !ok {
+		return
+	}
+	store, err := cache.Open(cache.NamespaceProbes)
+	if err != nil {
+		return
+	}
+	fp.Output = output
+	fp.UsedCmd = usedCmd
+	store.Put(probeKey(kind, fp), fp)
+}
+
+// probeKey 缓存键包含程序名：busybox、toybox 中 ls、cp 等命令都指向同一个二进制文件，帮助输出各不相同
+func probeKey(kind string, fp probeEntry) string {
+	return kind + ":" + fp.Program + ":" + fp.Path
+}
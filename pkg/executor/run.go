@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// userShell 返回用户的 Shell 及执行命令字符串所需的参数
+// interactive 为 true 时以交互模式启动，以便使用 nvm 等在 rc 文件中定义的 Shell 函数
+func userShell(interactive bool) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "cmd", []string{"/C"}
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/bash"
+	}
+	if interactive {
+		return shell, []string{"-i", "-c"}
+	}
+	return shell, []string{"-c"}
+}
+
+// RunInShell 在用户的 Shell 中执行命令，继承当前终端的输入输出，返回命令的退出状态码
+// 命令被信号终止时按 Shell 的约定返回 128 + 信号值；命令无法启动时返回错误
+// Ctrl+C 由终端直接发给子进程，不会中断 ghp
+func RunInShell(command string, interactive bool) (int, error) {
+	shell, args := userShell(interactive)
+	cmd := exec.Command(shell, append(args, command)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if interactive && runtime.GOOS != "windows" {
+		FixTerminal()
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+// EditFile 使用 $VISUAL 或 $EDITOR 编辑文件，均未设置时使用 vi (Windows 上为 notepad)
+func EditFile(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	// 编辑器可以带参数，如 "code -w"
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
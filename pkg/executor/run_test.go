@@ -0,0 +1,23 @@
+//go:build !windows
+
+package executor
+
+import "testing"
+
+func TestRunInShellExitCode(t *testing.T) {
+	t.Setenv("SHELL", "/bin/sh")
+	tests := []struct {
+		command string
+		want    int
+	}{
+		{"true", 0},
+		{"exit 3", 3},
+		{"kill -TERM $$", 128 + 15}, // 被信号终止时与 Shell 的约定一致
+	}
+	for _, tt := range tests {
+		code, err := RunInShell(tt.command, false)
+		if err != nil || code != tt.want {
+			t.Errorf("RunInShell(%q) = %d, %v, want %d", tt.command, code, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Sandbox 是否在沙箱中执行帮助/版本探测，可由配置关闭
+// 探测的程序和参数可能来自 AI，沙箱中没有网络、文件系统只读，且不会拿到 API Key 等环境变量
+var Sandbox = true
+
+// errSandboxUnavailable 当前系统无法创建沙箱，如非 Linux 系统或禁用了非特权用户命名空间
+var errSandboxUnavailable = errors.New("sandbox unavailable")
+
+// sandboxBroken 创建沙箱失败后不再尝试，之后的探测直接执行
+var sandboxBroken bool
+
+// probeOutput 执行一次帮助/版本探测，返回标准输出和标准错误的合并内容，env 为额外的环境变量
+// 优先在沙箱中执行，无法创建沙箱时退回以当前用户的权限直接执行
+func probeOutput(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	if Sandbox && !sandboxBroken {
+		out, err := runSandboxed(ctx, append(sandboxEnv(), env...), name, args)
+		if !errors.Is(err, errSandboxUnavailable) {
+			return out, err
+		}
+		sandboxBroken = true
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd.CombinedOutput()
+}
+
+// sandboxEnvKeep 沙箱中保留的环境变量：查找程序、加载 Shell 配置和决定输出语言所需的变量
+var sandboxEnvKeep = []string{"PATH", "HOME", "USER", "LOGNAME", "SHELL", "LANG", "LANGUAGE", "TERM", "TZ"}
+
+// sandboxEnv 返回清理后的环境变量，API Key、令牌等其他变量一律不传入沙箱
+func sandboxEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if slices.Contains(sandboxEnvKeep, name) || strings.HasPrefix(name, "LC_") {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
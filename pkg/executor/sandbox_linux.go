@@ -0,0 +1,132 @@
+//go:build linux
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandboxHelper 沙箱辅助进程的 argv[0]
+// Go 无法在 fork 与 exec 之间执行代码，因此 ghp 在新的命名空间中重新执行自身，
+// 由辅助进程完成挂载和资源限制后再 exec 探测命令
+const sandboxHelper = "ghp-sandbox-helper"
+
+// sandboxStatusFd 辅助进程向父进程报告隔离失败原因的文件描述符
+// 该描述符在 exec 时关闭，父进程读到 EOF 且没有内容即说明隔离成功
+const sandboxStatusFd = 3
+
+// sandboxLimits 探测命令的资源限制，只会调低已有的限制
+var sandboxLimits = []struct {
+	resource int
+	value    uint64
+}{
+	{unix.RLIMIT_CPU, 30},         // CPU 时间 (秒)
+	{unix.RLIMIT_DATA, 2 << 30},   // 堆和数据段
+	{unix.RLIMIT_FSIZE, 64 << 20}, // 单个文件大小
+	{unix.RLIMIT_NOFILE, 1024},    // 打开的文件数
+	{unix.RLIMIT_CORE, 0},         // 不生成 core 文件
+}
+
+func init() {
+	if len(os.Args) > 1 && os.Args[0] == sandboxHelper {
+		runSandboxHelper(os.Args[1:])
+	}
+}
+
+// runSandboxed 在新的用户、挂载、网络命名空间中执行命令
+// 命名空间不可用或隔离失败时返回 errSandboxUnavailable，此时命令不会被执行
+func runSandboxed(ctx context.Context, env []string, name string, args []string) ([]byte, error) {
+	status, statusW, err := os.Pipe()
+	if err != nil {
+		return nil, errSandboxUnavailable
+	}
+	defer status.Close()
+
+	uid, gid := os.Getuid(), os.Getgid()
+	cmd := exec.CommandContext(ctx, "/proc/self/exe", append([]string{name}, args...)...)
+	cmd.Args[0] = sandboxHelper
+	cmd.Env = env
+	cmd.ExtraFiles = []*os.File{statusW}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}},
+		Pdeathsig:   syscall.SIGKILL,
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err = cmd.Start()
+	statusW.Close()
+	if err != nil {
+		return nil, errSandboxUnavailable
+	}
+	reason, _ := io.ReadAll(status)
+	err = cmd.Wait()
+	if len(reason) > 0 {
+		return nil, errSandboxUnavailable
+	}
+	return out.Bytes(), err
+}
+
+// runSandboxHelper 在辅助进程中完成隔离并执行探测命令，不会返回
+func runSandboxHelper(args []string) {
+	// no_new_privs 只对当前线程生效，必须在同一线程上 exec
+	runtime.LockOSThread()
+	syscall.CloseOnExec(sandboxStatusFd)
+	status := os.NewFile(sandboxStatusFd, "sandbox-status")
+	if err := isolate(); err != nil {
+		fmt.Fprintf(status, "sandbox: %v", err)
+		os.Exit(1)
+	}
+
+	// 找不到程序或无法执行时与 Shell 的行为一致，不视为沙箱不可用
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(127)
+	}
+	err = syscall.Exec(path, args, os.Environ())
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(126)
+}
+
+// isolate 将整个文件系统设为只读，禁止提权并设置资源限制
+// /tmp 同样只读：替换为空目录会让位于 /tmp 下的程序无法被探测
+// 网络隔离由新的网络命名空间完成，其中只有未启用的回环网卡
+func isolate() error {
+	// 挂载变化不传播回宿主
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("make mounts private: %w", err)
+	}
+	// 需要 Linux 5.12 及以上，旧内核上无法递归设为只读，视为沙箱不可用
+	attr := &unix.MountAttr{Attr_set: unix.MOUNT_ATTR_RDONLY}
+	if err := unix.MountSetattr(unix.AT_FDCWD, "/", unix.AT_RECURSIVE, attr); err != nil {
+		return fmt.Errorf("remount read-only: %w", err)
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("no_new_privs: %w", err)
+	}
+	for _, l := range sandboxLimits {
+		var rlim unix.Rlimit
+		if err := unix.Getrlimit(l.resource, &rlim); err != nil {
+			return fmt.Errorf("getrlimit: %w", err)
+		}
+		rlim.Cur = min(rlim.Cur, l.value)
+		rlim.Max = min(rlim.Max, l.value)
+		if err := unix.Setrlimit(l.resource, &rlim); err != nil {
+			return fmt.Errorf("setrlimit: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package executor
+
+import "context"
+
+// runSandboxed 目前只有 Linux 支持沙箱，其他系统直接执行探测命令
+func runSandboxed(ctx context.Context, env []string, name string, args []string) ([]byte, error) {
+	return nil, errSandboxUnavailable
+}
@@ -0,0 +1,118 @@
+package helpparse
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// flagName 选项名，允许 --[no-]color 形式
+	flagName = regexp.MustCompile(`^--?(?:\[no-?\])?[A-Za-z0-9?#@][\w.-]*`)
+	// 选项后的参数写法
+	optionalArg = regexp.MustCompile(`^\[=?([^\]]+)\]`)                         // --color[=WHEN]、-i[SUFFIX]
+	equalsArg   = regexp.MustCompile(`^=(?:\[-\])?([^\s,\]]+)`)                 // --width=COLS、--lines=[-]NUM
+	angleArg    = regexp.MustCompile(`^ ?(\[)?<([^>]+)>\]?(?:\.\.\.)?`)         // --regexp <PATTERN>、[<N>]
+	wordArg     = regexp.MustCompile(`^ ([A-Za-z][\w-]*(?:\.\.\.)?)(?:$|  |,)`) // -o FILE、--kubeconfig string
+	choicesArg  = regexp.MustCompile(`^ (\{[^}\s]+\})(?:$|  |,)`)               // argparse: --level {1,2,3}
+	// 多个别名之间的分隔符
+	aliasSep = regexp.MustCompile(`^(?:,\s*|\s*\|\s*|\s*/\s*| or )`)
+	// usageCluster BSD 风格用法行中的单字母选项组合，如 [-AaCcdFf1%,]
+	usageCluster = regexp.MustCompile(`\[-([^-\s\]|][^\s\]|]+)\]`)
+	// usageFlag 用法行中的选项
+	usageFlag = regexp.MustCompile(`(?:^|[\s\[(|])(--?(?:\[no-?\])?[A-Za-z0-9?#@][\w.-]*)`)
+	// usageArg 用法行中紧跟选项的参数，如 [-D format]、-t DIRECTORY、[-C <path>]
+	usageArg = regexp.MustCompile(`^(?:(\[)?=|\[=| )(?:<([^>]+)>|([A-Za-z][\w-]*))(\]|\s|$)?`)
+)
+
+// parseFlag 解析选项说明行，如 "-a, --all  do not ignore entries starting with ."
+// 选项部分之后必须是行尾或至少两个空格分隔的说明，否则不视为选项说明行
+func parseFlag(trimmed string) (Flag, bool) {
+	var f Flag
+	s := trimmed
+	for {
+		name := flagName.FindString(s)
+		if name == "" {
+			break
+		}
+		f.Names = append(f.Names, expandNegation(name)...)
+		s = s[len(name):]
+
+		if m := optionalArg.FindStringSubmatch(s); m != nil {
+			f.Arg, f.OptionalArg = strings.Trim(m[1], "<>"), true
+			s = s[len(m[0]):]
+		} else if m := equalsArg.FindStringSubmatch(s); m != nil {
+			f.Arg = strings.Trim(m[1], "<>")
+			s = s[len(m[0]):]
+		} else if m := angleArg.FindStringSubmatch(s); m != nil {
+			f.Arg, f.OptionalArg = m[2], m[1] != ""
+			s = s[len(m[0]):]
+		} else if m := wordArg.FindStringSubmatch(s); m != nil {
+			f.Arg = m[1]
+			s = s[len(" "+m[1]):]
+		} else if m := choicesArg.FindStringSubmatch(s); m != nil {
+			f.Arg = m[1]
+			s = s[len(" "+m[1]):]
+		}
+
+		sep := aliasSep.FindString(s)
+		if sep == "" {
+			break
+		}
+		s = s[len(sep):]
+	}
+	if len(f.Names) == 0 {
+		return f, false
+	}
+	if s != "" && !strings.HasPrefix(s, "  ") {
+		return f, false
+	}
+	f.Description = strings.TrimSpace(s)
+	return f, true
+}
+
+// expandNegation 将 --[no-]color 展开为 --color 和 --no-color
+func expandNegation(name string) []string {
+	for _, neg := range []string{"[no-]", "[no]"} {
+		if before, after, ok := strings.Cut(name, neg); ok {
+			return []string{before + after, before + strings.Trim(neg, "[]") + after}
+		}
+	}
+	return []string{strings.TrimRight(name, ".-")}
+}
+
+// usageFlags 从用法行中补充选项列表中没有的选项
+// git、BSD 工具等只在用法行中列出选项，如 [-C <path>]、[-AaCc]
+func (p *parser) usageFlags() {
+	for _, usage := range p.help.Usage {
+		for _, m := range usageCluster.FindAllStringSubmatch(usage, -1) {
+			for _, r := range m[1] {
+				p.addUsageFlag(Flag{Names: []string{"-" + string(r)}})
+			}
+		}
+		usage = usageCluster.ReplaceAllString(usage, "")
+		for _, m := range usageFlag.FindAllStringSubmatchIndex(usage, -1) {
+			name := usage[m[2]:m[3]]
+			rest := usage[m[1]:]
+			f := Flag{Names: expandNegation(name)}
+			if a := usageArg.FindStringSubmatch(rest); a != nil {
+				arg := a[2] + a[3]
+				// 空格后的小写单词可能是下一个位置参数，只有紧接 ] 时才视为参数，如 [-D format]
+				if a[2] != "" || !strings.HasPrefix(a[0], " ") || unicode.IsUpper(rune(arg[0])) || a[4] == "]" {
+					f.Arg = arg
+					f.OptionalArg = a[1] != "" || strings.HasPrefix(a[0], "[=")
+				}
+			}
+			p.addUsageFlag(f)
+		}
+	}
+}
+
+func (p *parser) addUsageFlag(f Flag) {
+	for _, name := range f.Names {
+		if p.help.Flag(name) != nil {
+			return
+		}
+	}
+	p.help.Flags = append(p.help.Flags, f)
+}
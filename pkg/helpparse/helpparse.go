@@ -0,0 +1,353 @@
+// Package helpparse 将程序的 --help 输出解析为结构化的用法、选项和子命令
+// 支持 GNU getopt、cobra、clap、argparse、Go flag 以及 BSD 手册风格的帮助文档，
+// 为命令校验、补全和离线展示提供不依赖大模型的确定性数据
+package helpparse
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Help 解析后的帮助文档
+type Help struct {
+	Summary     string       // 开头的简介段落
+	Usage       []string     // 用法行，不含 "Usage:" 前缀
+	Flags       []Flag       // 选项
+	Subcommands []Subcommand // 子命令
+}
+
+// Flag 一个选项及其别名
+type Flag struct {
+	Names       []string // 包含前缀，如 -a、--all
+	Arg         string   // 参数占位符，如 FILE，不需要参数时为空
+	OptionalArg bool     // 参数可省略，如 --color[=WHEN]
+	Description string
+}
+
+// Subcommand 一个子命令及其别名
+type Subcommand struct {
+	Names       []string
+	Description string
+}
+
+// Flag 按名称查找选项，找不到时返回 nil
+func (h *Help) Flag(name string) *Flag {
+	for i := range h.Flags {
+		if slices.Contains(h.Flags[i].Names, name) {
+			return &h.Flags[i]
+		}
+	}
+	return nil
+}
+
+// Subcommand 按名称或别名查找子命令，找不到时返回 nil
+func (h *Help) Subcommand(name string) *Subcommand {
+	for i := range h.Subcommands {
+		if slices.Contains(h.Subcommands[i].Names, name) {
+			return &h.Subcommands[i]
+		}
+	}
+	return nil
+}
+
+// 帮助文档中的小节类型
+type section int
+
+const (
+	sectionNone       section = iota
+	sectionUsage              // 用法
+	sectionOptions            // 选项列表
+	sectionCommands           // 子命令列表
+	sectionPositional         // argparse 的位置参数，其中 {a,b} 为子命令
+	sectionName               // man 手册的 NAME 小节，作为简介
+	sectionOther
+)
+
+var (
+	// subcommandLine 子命令列表中的一行: 名称 (可带逗号分隔的别名，或 argparse 的 "list (ls)")，至少两个空格后为说明
+	subcommandLine = regexp.MustCompile(`^([A-Za-z][\w:.-]*(?:,\s*[A-Za-z][\w:.-]*)*)(?: \(([\w:.-]+(?:,\s*[\w:.-]+)*)\))?(?:\s{2,}(.*))?$`)
+	// choicesPattern argparse 风格的子命令集合，如 {start,stop}
+	choicesPattern = regexp.MustCompile(`^\{([\w-]+(?:,[\w-]+)*)\}(?:\s{2,}(.*))?$`)
+	// manHeading man 手册的小节标题，全大写且没有冒号，如 SYNOPSIS、OPTIONS、SEE ALSO
+	manHeading = regexp.MustCompile(`^[A-Z][A-Z ]{3,}$`)
+	// manTitle man 手册的页眉，如 "CP(1)    General Commands Manual    CP(1)"
+	manTitle = regexp.MustCompile(`\S\(\d\w*\)\s*$`)
+	// optionError 不支持 --help 的程序输出的错误行，如 BSD ls 的 "ls: unrecognized option `--help'"
+	optionError = regexp.MustCompile(`(?i)\b(?:unrecognized|illegal|invalid|unknown) option\b`)
+)
+
+type parser struct {
+	help    *Help
+	section section
+	summary []string
+	// 进入用法小节时已有的用法行数，"Usage:" 与用法行之间允许有空行
+	usageStart int
+	// 用法小节中第一行用法的缩进，缩进更深的行是上一行的延续；"Usage:" 后直接跟用法时为 -1
+	usageLevel int
+
+	// 上一个选项/子命令及其缩进，缩进更深的后续行为其说明的延续
+	flag      *Flag
+	sub       *Subcommand
+	lastLevel int
+	// argparse 中 {a,b} 所在的缩进，更深的行是各子命令的说明
+	choiceLevel int
+}
+
+// Parse 解析帮助文档
+func Parse(text string) *Help {
+	p := &parser{help: &Help{}, choiceLevel: -1}
+	paragraphEnded := false
+	for _, raw := range strings.Split(text, "\n") {
+		line := expandTabs(strings.TrimRight(raw, " \t\r"))
+		trimmed := strings.TrimLeft(line, " ")
+		level := len(line) - len(trimmed)
+		if trimmed == "" {
+			if p.section == sectionUsage && len(p.help.Usage) > p.usageStart {
+				p.section = sectionNone
+			}
+			paragraphEnded = len(p.summary) > 0
+			continue
+		}
+		if level == 0 && p.header(trimmed) {
+			continue
+		}
+		if level == 0 && !strings.HasPrefix(trimmed, "-") {
+			// 顶格的普通文字: 开头的简介段落，或 git 帮助中子命令分组的说明
+			if p.section == sectionUsage {
+				p.section = sectionNone
+			}
+			if p.section == sectionNone && !paragraphEnded && p.help.Flags == nil && p.help.Subcommands == nil &&
+				!manTitle.MatchString(trimmed) && !optionError.MatchString(trimmed) {
+				p.summary = append(p.summary, trimmed)
+			}
+			p.flag, p.sub = nil, nil
+			continue
+		}
+		p.line(line, trimmed, level)
+	}
+
+	p.help.Summary = strings.Join(p.summary, " ")
+	p.usageFlags()
+	return p.help
+}
+
+// header 识别顶格的小节标题，如 "Usage:"、"Options:"、"Available Commands:"，以及 man 手册的 "SYNOPSIS"、"OPTIONS"
+func (p *parser) header(trimmed string) bool {
+	lower := strings.ToLower(trimmed)
+	if manHeading.MatchString(trimmed) {
+		switch {
+		case trimmed == "SYNOPSIS":
+			p.startUsage()
+			return true
+		case trimmed == "NAME" && len(p.summary) == 0:
+			p.section = sectionName
+			return true
+		}
+		trimmed += ":"
+	}
+	if strings.HasPrefix(lower, "usage of ") && strings.HasSuffix(lower, ":") {
+		// Go flag: "Usage of prog:" 之后直接是选项列表
+		p.section = sectionOptions
+		return true
+	}
+	if strings.HasPrefix(lower, "usage:") {
+		p.startUsage()
+		if usage := strings.TrimSpace(trimmed[len("usage:"):]); usage != "" {
+			p.addUsage(usage)
+			p.usageLevel = -1
+		}
+		return true
+	}
+	if !strings.HasSuffix(trimmed, ":") || strings.HasPrefix(trimmed, "-") {
+		return false
+	}
+	p.flag, p.sub = nil, nil
+	p.choiceLevel = -1
+	switch {
+	case strings.Contains(lower, "positional"):
+		p.section = sectionPositional
+	case strings.Contains(lower, "command") || strings.Contains(lower, "子命令"):
+		p.section = sectionCommands
+	case strings.Contains(lower, "option") || strings.Contains(lower, "flag") || strings.Contains(lower, "argument") || strings.Contains(lower, "选项"):
+		p.section = sectionOptions
+	default:
+		p.section = sectionOther
+	}
+	return true
+}
+
+// startUsage 进入用法小节
+func (p *parser) startUsage() {
+	p.section = sectionUsage
+	p.usageStart = len(p.help.Usage)
+	p.usageLevel = 0
+}
+
+// line 处理缩进的行
+func (p *parser) line(line, trimmed string, level int) {
+	switch p.section {
+	case sectionUsage:
+		// "Usage: foo [options]" 之后直接列出选项 (没有 "Options:" 标题) 时，带说明的选项行不是用法的延续
+		if p.usageLevel >= 0 || !describedFlag(trimmed) {
+			p.usageLine(trimmed, level)
+			return
+		}
+		p.section = sectionOptions
+	case sectionName:
+		p.summary = append(p.summary, trimmed)
+		return
+	}
+	if strings.HasPrefix(trimmed, "-") {
+		if f, ok := parseFlag(trimmed); ok {
+			if p.help.Flag(f.Names[0]) != nil {
+				// 已出现过的选项，如 tar 帮助末尾列出的默认值 --rsh-command=/usr/bin/rsh
+				p.flag, p.sub = nil, nil
+				return
+			}
+			p.help.Flags = append(p.help.Flags, f)
+			p.flag, p.sub = &p.help.Flags[len(p.help.Flags)-1], nil
+			p.lastLevel = level
+			return
+		}
+	}
+	if p.continuation(trimmed, level) {
+		return
+	}
+
+	switch p.section {
+	case sectionCommands:
+		if m := subcommandLine.FindStringSubmatch(trimmed); m != nil {
+			p.addSubcommand(append(splitNames(m[1]), splitNames(m[2])...), m[3], level)
+		}
+	case sectionPositional:
+		if m := choicesPattern.FindStringSubmatch(trimmed); m != nil {
+			for _, name := range strings.Split(m[1], ",") {
+				p.addSubcommand([]string{name}, "", level)
+			}
+			p.choiceLevel = level
+			p.sub = nil
+			return
+		}
+		// {a,b} 之下缩进更深的行是各子命令的说明
+		if p.choiceLevel >= 0 && level > p.choiceLevel {
+			if m := subcommandLine.FindStringSubmatch(trimmed); m != nil {
+				if sub := p.help.Subcommand(m[1]); sub != nil {
+					sub.Description = m[3]
+					p.aliases(m[1], splitNames(m[2]))
+					p.sub, p.lastLevel = p.help.Subcommand(m[1]), level
+				}
+			}
+		}
+	}
+}
+
+// aliases 将 {a,b} 中单独列出的别名并入子命令，如 {list,ls} 下的 "list (ls)"
+func (p *parser) aliases(name string, aliases []string) {
+	for _, alias := range aliases {
+		p.help.Subcommands = slices.DeleteFunc(p.help.Subcommands, func(s Subcommand) bool {
+			return len(s.Names) == 1 && s.Names[0] == alias
+		})
+		sub := p.help.Subcommand(name)
+		if !slices.Contains(sub.Names, alias) {
+			sub.Names = append(sub.Names, alias)
+		}
+	}
+}
+
+// describedFlag 是否为带说明的选项行，如 "-x, --extra   do extra stuff"
+func describedFlag(trimmed string) bool {
+	if !strings.HasPrefix(trimmed, "-") {
+		return false
+	}
+	f, ok := parseFlag(trimmed)
+	return ok && f.Description != ""
+}
+
+// continuation 缩进比上一个选项/子命令更深的行，作为其说明的延续
+func (p *parser) continuation(trimmed string, level int) bool {
+	if level <= p.lastLevel {
+		return false
+	}
+	switch {
+	case p.flag != nil:
+		p.flag.Description = joinText(p.flag.Description, trimmed)
+	case p.sub != nil:
+		p.sub.Description = joinText(p.sub.Description, trimmed)
+	default:
+		return false
+	}
+	return true
+}
+
+func (p *parser) addSubcommand(names []string, desc string, level int) {
+	if p.help.Subcommand(names[0]) != nil {
+		return
+	}
+	p.help.Subcommands = append(p.help.Subcommands, Subcommand{Names: names, Description: strings.TrimSpace(desc)})
+	p.sub, p.flag = &p.help.Subcommands[len(p.help.Subcommands)-1], nil
+	p.lastLevel = level
+}
+
+// usageLine 处理用法小节中缩进的行
+func (p *parser) usageLine(trimmed string, level int) {
+	n := len(p.help.Usage)
+	switch {
+	case strings.HasPrefix(trimmed, "or:"):
+		// GNU: "  or:  cp [OPTION]... SOURCE... DIRECTORY"
+		p.addUsage(strings.TrimSpace(trimmed[len("or:"):]))
+	case n > p.usageStart && (strings.ContainsAny(trimmed[:1], "[<{-") || p.usageLevel > 0 && level > p.usageLevel):
+		// 多行用法，后续行以 [、<、{ 或选项开头对齐 (如 git、argparse)，或比第一行缩进更深 (如 man 手册的 SYNOPSIS)
+		p.help.Usage[n-1] += " " + trimmed
+	default:
+		if n == p.usageStart && p.usageLevel == 0 {
+			p.usageLevel = level
+		}
+		p.addUsage(trimmed)
+	}
+}
+
+// addUsage 添加用法行，组合了 --help 和 man 手册的文档中相同的用法只保留一次
+func (p *parser) addUsage(line string) {
+	if line != "" && !slices.Contains(p.help.Usage, line) {
+		p.help.Usage = append(p.help.Usage, line)
+	}
+}
+
+// splitNames 拆分逗号分隔的名称和别名
+func splitNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func joinText(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " " + b
+}
+
+// expandTabs 按 8 列制表位展开制表符，Go flag 等格式用制表符缩进说明
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := 8 - col%8
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		b.WriteRune(r)
+		col++
+	}
+	return b.String()
+}
@@ -0,0 +1,264 @@
+package helpparse
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+// wantFlag 期望解析出的选项，按第一个名称查找
+type wantFlag struct {
+	names    []string
+	arg      string
+	optional bool
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		summary     string
+		usage       []string
+		flags       []wantFlag
+		absent      []string   // 不应解析为选项的名称
+		subcommands [][]string // 全部子命令及别名，按出现顺序
+	}{
+		{
+			name:    "GNU getopt",
+			file:    "gnu_cp.txt",
+			summary: "Copy SOURCE to DEST, or multiple SOURCE(s) to DIRECTORY.",
+			usage: []string{
+				"cp [OPTION]... [-T] SOURCE DEST",
+				"cp [OPTION]... SOURCE... DIRECTORY",
+				"cp [OPTION]... -t DIRECTORY SOURCE...",
+			},
+			flags: []wantFlag{
+				{names: []string{"-a", "--archive"}},
+				{names: []string{"--backup"}, arg: "CONTROL", optional: true},
+				{names: []string{"-R", "-r", "--recursive"}},
+				{names: []string{"--sparse"}, arg: "WHEN"},
+				{names: []string{"-S", "--suffix"}, arg: "SUFFIX"},
+				{names: []string{"-t", "--target-directory"}, arg: "DIRECTORY"},
+				{names: []string{"--context"}, arg: "CTX", optional: true},
+				{names: []string{"--version"}},
+			},
+		},
+		{
+			name:    "git",
+			file:    "git.txt",
+			summary: "",
+			usage: []string{
+				"git [-v | --version] [-h | --help] [-C <path>] [-c <name>=<value>] [--exec-path[=<path>]] [--html-path] [--man-path] [--info-path] [-p | --paginate | -P | --no-pager] [--no-replace-objects] [--bare] [--git-dir=<path>] [--work-tree=<path>] [--namespace=<name>] [--super-prefix=<path>] [--config-env=<name>=<envvar>] <command> [<args>]",
+			},
+			flags: []wantFlag{
+				{names: []string{"-C"}, arg: "path"},
+				{names: []string{"--exec-path"}, arg: "path", optional: true},
+				{names: []string{"--git-dir"}, arg: "path"},
+				{names: []string{"--no-pager"}},
+			},
+			subcommands: [][]string{
+				{"clone"}, {"init"}, {"add"}, {"mv"}, {"restore"}, {"rm"}, {"bisect"}, {"diff"}, {"grep"}, {"log"}, {"show"},
+				{"status"}, {"branch"}, {"commit"}, {"merge"}, {"rebase"}, {"reset"}, {"switch"}, {"tag"}, {"fetch"}, {"pull"}, {"push"},
+			},
+		},
+		{
+			name:    "cobra",
+			file:    "cobra_ghp.txt",
+			summary: "ghp is a CLI tool that uses AI to explain commands and provide usage examples.",
+			usage:   []string{"ghp [command] [subcommand...] [flags]", "ghp [command]"},
+			flags: []wantFlag{
+				{names: []string{"-a", "--analyze"}},
+				{names: []string{"--format"}, arg: "string"},
+				{names: []string{"-o", "--output"}, arg: "string"},
+				{names: []string{"--no-cache"}},
+				{names: []string{"--temperature"}, arg: "float32"},
+			},
+			subcommands: [][]string{{"cache"}, {"config"}, {"export"}, {"help"}, {"prompts"}},
+		},
+		{
+			name:    "clap",
+			file:    "clap_cargo.txt",
+			summary: "Rust's package manager",
+			usage: []string{
+				"cargo [+toolchain] [OPTIONS] [COMMAND]",
+				"cargo [+toolchain] [OPTIONS] -Zscript <MANIFEST_RS> [ARGS]...",
+			},
+			flags: []wantFlag{
+				{names: []string{"-V", "--version"}},
+				{names: []string{"-v", "--verbose"}},
+				{names: []string{"--explain"}, arg: "CODE"},
+				{names: []string{"-C"}, arg: "DIRECTORY"},
+				{names: []string{"--config"}, arg: "KEY=VALUE|PATH"},
+			},
+			// "...  See all commands with --list" 不是子命令
+			subcommands: [][]string{
+				{"build", "b"}, {"check", "c"}, {"clean"}, {"doc", "d"}, {"new"}, {"init"}, {"add"}, {"remove"},
+				{"run", "r"}, {"test", "t"}, {"bench"}, {"update"}, {"search"}, {"publish"}, {"install"}, {"uninstall"},
+			},
+		},
+		{
+			name:    "clap subcommand",
+			file:    "clap_cargo_build.txt",
+			summary: "Compile a local package and all of its dependencies",
+			usage:   []string{"cargo build [OPTIONS]"},
+			flags: []wantFlag{
+				{names: []string{"-p", "--package"}, arg: "SPEC", optional: true},
+				{names: []string{"--exclude"}, arg: "SPEC"},
+				{names: []string{"-F", "--features"}, arg: "FEATURES"},
+				{names: []string{"-j", "--jobs"}, arg: "N"},
+				{names: []string{"--timings"}, arg: "FMTS", optional: true},
+				{names: []string{"--profile"}, arg: "PROFILE-NAME"},
+			},
+		},
+		{
+			name:    "argparse",
+			file:    "argparse_backup.txt",
+			summary: "Back up directories to a remote store.",
+			usage:   []string{"backup [-h] [-v] [-c FILE] [--dry-run] [--level {1,2,3}] [--color | --no-color] {create,restore,list,ls} ..."},
+			flags: []wantFlag{
+				{names: []string{"-h", "--help"}},
+				{names: []string{"-c", "--config"}, arg: "FILE"},
+				{names: []string{"--dry-run"}},
+				{names: []string{"--level"}, arg: "{1,2,3}"},
+				{names: []string{"--color", "--no-color"}},
+			},
+			subcommands: [][]string{{"create"}, {"restore"}, {"list", "ls"}},
+		},
+		{
+			name:    "Go flag",
+			file:    "goflag_webserver.txt",
+			summary: "",
+			usage:   nil,
+			flags: []wantFlag{
+				{names: []string{"-addr"}, arg: "address"},
+				{names: []string{"-config"}, arg: "file"},
+				{names: []string{"-debug"}},
+				{names: []string{"-timeout"}, arg: "duration"},
+				{names: []string{"-v"}},
+				{names: []string{"-workers"}, arg: "int"},
+			},
+		},
+		{
+			name:    "BSD usage",
+			file:    "bsd_ls.txt",
+			summary: "",
+			usage:   []string{"ls [-@ABCFGHILOPRSTUWXabcdefghiklmnopqrstuvwxy1%,] [--color=when] [-D format] [file ...]"},
+			flags: []wantFlag{
+				{names: []string{"-@"}},
+				{names: []string{"-l"}},
+				{names: []string{"-1"}},
+				{names: []string{"--color"}, arg: "when"},
+				{names: []string{"-D"}, arg: "format"},
+			},
+			absent: []string{"--help", "-j"},
+		},
+		{
+			name:    "BSD man page",
+			file:    "bsd_cp.txt",
+			summary: "cp – copy files",
+			usage: []string{
+				"cp [-R [-H | -L | -P]] [-f | -i | -n] [-alNpsvx] source_file target_file",
+				"cp [-R [-H | -L | -P]] [-f | -i | -n] [-alNpsvx] source_file ... target_directory",
+				"cp [-f | -i | -n] [-alNPpsvx] source_file target_file",
+				"cp [-f | -i | -n] [-alNPpsvx] source_file ... target_directory",
+			},
+			flags: []wantFlag{
+				{names: []string{"-H"}},
+				{names: []string{"-L", "--dereference"}},
+				{names: []string{"-R", "--recursive"}},
+				{names: []string{"-a", "--archive"}},
+				{names: []string{"-N"}}, // 只出现在 SYNOPSIS 中
+				{names: []string{"-n"}},
+			},
+		},
+		{
+			name:    "usage followed by options",
+			file:    "inline_usage.txt",
+			summary: "",
+			usage:   []string{"foo [options] <file>"},
+			flags: []wantFlag{
+				{names: []string{"-x", "--extra"}},
+				{names: []string{"-o", "--output"}, arg: "FILE"},
+				{names: []string{"-h", "--help"}},
+			},
+		},
+		{
+			name:    "wrapped usage followed by options",
+			file:    "inline_usage_wrapped.txt",
+			summary: "",
+			usage:   []string{"sync [-n] [--delete] SRC -r REMOTE DEST"},
+			flags: []wantFlag{
+				{names: []string{"-n", "--dry-run"}},
+				{names: []string{"-d", "--delete"}},
+				{names: []string{"-r", "--remote"}, arg: "NAME"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile("testdata/" + tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := Parse(string(data))
+			if h.Summary != tt.summary {
+				t.Errorf("Summary = %q, want %q", h.Summary, tt.summary)
+			}
+			if !slices.Equal(h.Usage, tt.usage) {
+				t.Errorf("Usage = %q, want %q", h.Usage, tt.usage)
+			}
+			for _, want := range tt.flags {
+				f := h.Flag(want.names[0])
+				if f == nil {
+					t.Errorf("flag %s not found", want.names[0])
+					continue
+				}
+				if !slices.Equal(f.Names, want.names) || f.Arg != want.arg || f.OptionalArg != want.optional {
+					t.Errorf("flag %s = %q arg %q optional %v, want %q arg %q optional %v",
+						want.names[0], f.Names, f.Arg, f.OptionalArg, want.names, want.arg, want.optional)
+				}
+			}
+			for _, name := range tt.absent {
+				if h.Flag(name) != nil {
+					t.Errorf("flag %s should not be parsed", name)
+				}
+			}
+			var subs [][]string
+			for _, s := range h.Subcommands {
+				subs = append(subs, s.Names)
+			}
+			if !slices.EqualFunc(subs, tt.subcommands, slices.Equal) {
+				t.Errorf("Subcommands = %q, want %q", subs, tt.subcommands)
+			}
+		})
+	}
+}
+
+// 选项说明行与普通文字的区分
+func TestParseFlag(t *testing.T) {
+	tests := []struct {
+		line  string
+		ok    bool
+		names []string
+		arg   string
+	}{
+		{"-a, --all                  do not ignore entries starting with .", true, []string{"-a", "--all"}, ""},
+		{"--[no-]color  colorize output", true, []string{"--color", "--no-color"}, ""},
+		{"-o, --output=FILE  write to FILE", true, []string{"-o", "--output"}, "FILE"},
+		{"-e PATTERN, --regexp=PATTERN  use PATTERN", true, []string{"-e", "--regexp"}, "PATTERN"},
+		{"--kubeconfig string   Path to the kubeconfig file", true, []string{"--kubeconfig"}, "string"},
+		{"-n <N>", true, []string{"-n"}, "N"},
+		{"-- this is prose, not an option", false, nil, ""},
+		{"-x is described in the next paragraph", false, nil, ""},
+	}
+	for _, tt := range tests {
+		f, ok := parseFlag(tt.line)
+		if ok != tt.ok {
+			t.Errorf("parseFlag(%q) ok = %v, want %v", tt.line, ok, tt.ok)
+			continue
+		}
+		if ok && (!slices.Equal(f.Names, tt.names) || f.Arg != tt.arg) {
+			t.Errorf("parseFlag(%q) = %q arg %q, want %q arg %q", tt.line, f.Names, f.Arg, tt.names, tt.arg)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Package i18n 提供界面文案的多语言支持
+// 文案按语言存放在 locales/<lang>.yaml 中，新增语言只需添加对应的文件
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLanguage 无法从环境变量判断语言时使用的默认语言
+const DefaultLanguage = "zh"
+
+// fallbackLanguage 当前语言缺少某条文案时使用的语言
+const fallbackLanguage = "en"
+
+//go:embed locales/*.yaml
+var localeFS embed.FS
+
+var (
+	loadOnce sync.Once
+	catalogs map[string]map[string]string
+
+	mu      sync.RWMutex
+	current = initialLanguage()
+)
+
+// load 解析内置的文案文件
+func load() {
+	catalogs = make(map[string]map[string]string)
+	files, _ := localeFS.ReadDir("locales")
+	for _, f := range files {
+		data, err := localeFS.ReadFile("locales/" + f.Name())
+		if err != nil {
+			continue
+		}
+		messages := make(map[string]string)
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: 解析 %s 失败: %v", f.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(f.Name(), path.Ext(f.Name()))] = messages
+	}
+}
+
+// initialLanguage 程序启动时的语言，配置文件加载后可通过 SetLanguage 覆盖
+func initialLanguage() string {
+	if lang := Normalize(os.Getenv("GHP_LANGUAGE")); lang != "" {
+		return lang
+	}
+	return Detect()
+}
+
+// Detect 依次从 LC_ALL、LC_MESSAGES、LANG 判断系统语言
+func Detect() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if lang := Normalize(os.Getenv(env)); lang != "" {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// Normalize 将 zh_CN.UTF-8、en-US 等写法统一为语言代码，C/POSIX 视为未设置
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if i := strings.IndexAny(lang, "_-"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "c" || lang == "posix" {
+		return ""
+	}
+	return lang
+}
+
+// SetLanguage 切换界面语言，空值时保持不变
+func SetLanguage(lang string) {
+	if lang = Normalize(lang); lang == "" {
+		return
+	}
+	mu.Lock()
+	current = lang
+	mu.Unlock()
+}
+
+// Language 当前界面语言
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Languages 内置文案的语言列表
+func Languages() []string {
+	loadOnce.Do(load)
+	var langs []string
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// T 返回当前语言的文案，带参数时按 fmt.Sprintf 格式化
+// 当前语言缺少该文案时依次回退到英文和 key 本身
+func T(key string, args ...any) string {
+	return TFor(Language(), key, args...)
+}
+
+// TFor 返回指定语言的文案，用于提示词等跟随回答语言而不是界面语言的内容
+// lang 为空时使用默认语言，缺少该文案时的回退规则与 T 相同
+func TFor(lang, key string, args ...any) string {
+	loadOnce.Do(load)
+	if lang = Normalize(lang); lang == "" {
+		lang = DefaultLanguage
+	}
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[fallbackLanguage][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Name 语言在提示词中的名称，如 zh -> 中文
+// 没有内置文案的语言直接使用语言代码，大模型同样可以理解
+func Name(lang string) string {
+	loadOnce.Do(load)
+	lang = Normalize(lang)
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if name, ok := catalogs[lang]["language.name"]; ok {
+		return name
+	}
+	return lang
+}
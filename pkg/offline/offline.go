@@ -0,0 +1,78 @@
+// Package offline 在 AI 服务不可用时，由本地解析的帮助文档生成速查表
+// 生成的回答带有 Offline 标记，展示时会注明并非 AI 回答
+package offline
+
+import (
+	"regexp"
+	"strings"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/helpparse"
+)
+
+// versionPattern 版本号，如 9.4、v1.25.4、2.43.0-rc1、go1.25.4 中的 1.25.4
+var versionPattern = regexp.MustCompile(`(?:^|[^\w.])v?(\d+(?:\.\d+)+(?:-[\w.]+)?)|\b[a-z]+(\d+(?:\.\d+)+)\b`)
+
+// Version 从版本命令的输出中提取版本号，找不到时返回空字符串
+func Version(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if m := versionPattern.FindStringSubmatch(line); m != nil {
+			return m[1] + m[2]
+		}
+	}
+	return ""
+}
+
+// CheatSheet 根据帮助文档生成速查表，选项按帮助文档中的顺序全部列出
+func CheatSheet(help, versionOutput, location string) *ai.CheatSheet {
+	h := helpparse.Parse(help)
+	// 与模型的回答保持一致，列表字段不为 null
+	sheet := &ai.CheatSheet{
+		Summary:  h.Summary,
+		Location: location,
+		Version:  Version(versionOutput),
+		Install:  []string{},
+		Usage:    append([]string{}, h.Usage...),
+		Options:  []ai.Option{},
+		Examples: []ai.Example{},
+		Offline:  true,
+	}
+	for _, f := range h.Flags {
+		sheet.Options = append(sheet.Options, option(f))
+	}
+	return sheet
+}
+
+// Subcommand 查询的是帮助文档中列出的子命令或选项时，生成对应的卡片
+func Subcommand(help, query string) (*ai.SubcommandCard, bool) {
+	h := helpparse.Parse(help)
+	query = strings.TrimSpace(query)
+	card := &ai.SubcommandCard{Kind: ai.SubcommandOK, Subcommand: query, Options: []ai.Option{}, Examples: []ai.Example{}, Offline: true}
+	if sub := h.Subcommand(query); sub != nil {
+		card.Summary = sub.Description
+		return card, true
+	}
+	if f := h.Flag(query); f != nil {
+		card.Summary = f.Description
+		card.Options = []ai.Option{option(*f)}
+		return card, true
+	}
+	return nil, false
+}
+
+// option 将解析出的选项转为速查表中的写法，如 -w, --width=COLS
+func option(f helpparse.Flag) ai.Option {
+	flag := strings.Join(f.Names, ", ")
+	if f.Arg != "" {
+		last := f.Names[len(f.Names)-1]
+		switch {
+		case f.OptionalArg:
+			flag += "[=" + f.Arg + "]"
+		case strings.HasPrefix(last, "--"):
+			flag += "=" + f.Arg
+		default:
+			flag += " " + f.Arg
+		}
+	}
+	return ai.Option{Flag: flag, Description: f.Description}
+}
@@ -0,0 +1,220 @@
+package render
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/i18n"
+)
+
+// 文档格式
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats 支持导出的文档格式
+var Formats = []string{FormatMarkdown, FormatHTML}
+
+// Ext 文档格式对应的文件扩展名
+func Ext(format string) string {
+	if format == FormatHTML {
+		return ".html"
+	}
+	return ".md"
+}
+
+// IndexEntry 索引页中的一项
+type IndexEntry struct {
+	Name    string // 命令名
+	File    string // 文档文件名，相对于索引页
+	Summary string
+	Version string
+}
+
+// WriteDocument 将回答写为指定格式的文档
+func WriteDocument(w io.Writer, format, title string, answer ai.Answer) error {
+	doc := newDocument(title, answer)
+	switch format {
+	case FormatMarkdown:
+		return writeMarkdown(w, doc)
+	case FormatHTML:
+		return writeHTML(w, doc)
+	}
+	return errors.New(i18n.T("render.err_format", format, strings.Join(Formats, ", ")))
+}
+
+// WriteIndex 生成指向各文档的索引页
+func WriteIndex(w io.Writer, format, title string, entries []IndexEntry) error {
+	entries = slices.Clone(entries)
+	for i := range entries {
+		entries[i].Summary = clean(entries[i].Summary)
+		entries[i].Version = clean(entries[i].Version)
+	}
+	switch format {
+	case FormatMarkdown:
+		return writeMarkdownIndex(w, title, entries)
+	case FormatHTML:
+		return writeHTMLIndex(w, title, entries)
+	}
+	return errors.New(i18n.T("render.err_format", format, strings.Join(Formats, ", ")))
+}
+
+// document 与输出格式无关的文档结构，Markdown 和 HTML 共用
+type document struct {
+	Title    string
+	Note     string // 标题下方的提示，如离线回答的说明
+	Summary  string
+	Fields   []docField
+	Sections []docSection
+}
+
+type docField struct {
+	Label, Value string
+	Code         bool // 以代码样式显示，如路径、命令
+}
+
+// docSection 文档中的一节，按内容类型只会设置其中一项
+type docSection struct {
+	Title    string
+	Ordered  []string     // 有序列表
+	Bullets  []string     // 无序列表
+	Code     []string     // 代码块
+	Header   [2]string    // 表头
+	Rows     []row        // 两列表格
+	Examples []ai.Example // 示例命令
+}
+
+func newDocument(title string, answer ai.Answer) document {
+	doc := document{Title: title}
+	switch a := answer.(type) {
+	case *ai.CheatSheet:
+		if a.Offline {
+			doc.Note = i18n.T("render.offline")
+		}
+		doc.Summary = clean(a.Summary)
+		doc.addField(i18n.T("render.location"), a.Location, true)
+		doc.addField(i18n.T("render.version"), a.Version, false)
+		doc.addSection(docSection{Title: i18n.T("render.install"), Ordered: cleanAll(a.Install)})
+		doc.addSection(docSection{Title: i18n.T("render.usage"), Code: cleanAll(a.Usage)})
+		doc.addSection(optionSection(i18n.T("render.options"), a.Options))
+		doc.addSection(docSection{Title: i18n.T("render.examples"), Examples: cleanExamples(a.Examples)})
+	case *ai.SubcommandCard:
+		if a.Offline {
+			doc.Note = i18n.T("render.offline")
+		}
+		doc.Summary = clean(a.Summary)
+		doc.addField(i18n.T("render.subcommand"), a.Subcommand, true)
+		doc.addSection(optionSection(i18n.T("render.options"), a.Options))
+		doc.addSection(docSection{Title: i18n.T("render.subcommand_examples"), Examples: cleanExamples(a.Examples)})
+	case *ai.CommandExplanation:
+		doc.Note = riskNote(a.Risk)
+		doc.Summary = clean(a.Summary)
+		doc.addField(i18n.T("render.command"), a.Command, true)
+		doc.addField(i18n.T("render.location"), a.Location, true)
+		doc.addSection(docSection{Title: i18n.T("render.risk"), Bullets: riskReasons(a.Risk)})
+		doc.addSection(partSection(a.Parts))
+		doc.addSection(docSection{Title: i18n.T("render.suggestions"), Bullets: cleanAll(a.Suggestions)})
+	case *ai.GeneratedCommand:
+		doc.Note = riskNote(a.Risk)
+		doc.addField(i18n.T("render.request"), a.Request, false)
+		doc.addSection(docSection{Title: i18n.T("render.generated"), Code: cleanAll([]string{a.Command})})
+		doc.addSection(docSection{Title: i18n.T("render.risk"), Bullets: riskReasons(a.Risk)})
+		doc.addSection(docSection{Title: i18n.T("render.verification"), Bullets: cleanAll(verificationNotes(a.Verification))})
+		doc.addSection(partSection(a.Parts))
+		doc.addSection(docSection{Title: i18n.T("render.suggestions"), Bullets: cleanAll(a.Suggestions)})
+	}
+	return doc
+}
+
+func (d *document) addField(label, value string, code bool) {
+	if value = clean(value); value != "" {
+		d.Fields = append(d.Fields, docField{Label: label, Value: value, Code: code})
+	}
+}
+
+// addSection 添加一节，没有内容的节会被跳过
+func (d *document) addSection(s docSection) {
+	if len(s.Ordered)+len(s.Bullets)+len(s.Code)+len(s.Rows)+len(s.Examples) > 0 {
+		d.Sections = append(d.Sections, s)
+	}
+}
+
+func optionSection(title string, options []ai.Option) docSection {
+	s := docSection{Title: title, Header: [2]string{i18n.T("render.option"), i18n.T("render.description")}}
+	for _, o := range options {
+		s.Rows = append(s.Rows, row{clean(o.Flag), clean(o.Description)})
+	}
+	return s
+}
+
+func partSection(parts []ai.CommandPart) docSection {
+	s := docSection{Title: i18n.T("render.parts"), Header: [2]string{i18n.T("render.token"), i18n.T("render.meaning")}}
+	for _, p := range parts {
+		s.Rows = append(s.Rows, row{clean(p.Token), clean(p.Meaning)})
+	}
+	return s
+}
+
+// verificationNotes 将生成命令的校验结果转为说明文字，未校验时返回空
+func verificationNotes(v *ai.Verification) []string {
+	if v == nil {
+		return nil
+	}
+	var notes []string
+	if v.Repaired {
+		notes = append(notes, i18n.T("render.verify_repaired", strings.Join(v.Flagged, ", ")), i18n.T("render.verify_original", v.Original))
+	}
+	if len(v.Unknown) > 0 {
+		notes = append(notes, i18n.T("render.verify_unknown", strings.Join(v.Unknown, ", ")))
+	}
+	if len(v.Unverified) > 0 {
+		notes = append(notes, i18n.T("render.verify_unverified", strings.Join(v.Unverified, ", ")))
+	}
+	if len(v.Unknown) == 0 && len(v.Unverified) == 0 && !v.Repaired {
+		notes = append(notes, i18n.T("render.verify_ok"))
+	}
+	return notes
+}
+
+// riskNote 风险等级的说明文字，未评估时返回空
+func riskNote(rk ai.Risk) string {
+	if rk.Level == "" {
+		return ""
+	}
+	return i18n.T("risk.level." + rk.Level)
+}
+
+// riskReasons 合并模型给出的原因和命中的本地规则，去掉重复项
+func riskReasons(rk ai.Risk) []string {
+	var reasons []string
+	for _, reason := range cleanAll(append([]string{rk.Reason}, rk.Rules...)) {
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons
+}
+
+func cleanAll(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if line = clean(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func cleanExamples(examples []ai.Example) []ai.Example {
+	var out []ai.Example
+	for _, e := range examples {
+		if cmd := clean(e.Command); cmd != "" {
+			out = append(out, ai.Example{Command: cmd, Description: exampleDescription(e), TLDR: e.TLDR})
+		}
+	}
+	return out
+}
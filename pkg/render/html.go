@@ -0,0 +1,118 @@
+package render
+
+import (
+	"html/template"
+	"io"
+
+	"ghp/pkg/i18n"
+)
+
+// htmlStyle 导出页面共用的样式，页面不依赖任何外部资源，可直接上传到 Wiki
+const htmlStyle = `
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #24292f; line-height: 1.6; }
+code, pre { font-family: SFMono-Regular, Consolas, Menlo, monospace; }
+code { background: #f6f8fa; padding: 0.1em 0.3em; border-radius: 4px; }
+pre { background: #f6f8fa; padding: 1em; border-radius: 6px; overflow-x: auto; }
+pre code { padding: 0; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #d0d7de; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.comment { color: #6e7781; }
+blockquote { margin: 1em 0; padding: 0.5em 1em; border-left: 4px solid #d4a72c; background: #fff8c5; }
+`
+
+// htmlFuncs 模板中使用的文案函数
+var htmlFuncs = template.FuncMap{
+	"lang": func() string { return i18n.T("render.html_lang") },
+	"t":    func(key string) string { return i18n.T(key) },
+}
+
+var htmlDocument = template.Must(template.New("document").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html lang="{{lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>` + htmlStyle + `</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if .Note}}
+<blockquote>{{.Note}}</blockquote>
+{{- end}}
+{{- if .Summary}}
+<p>{{.Summary}}</p>
+{{- end}}
+{{- if .Fields}}
+<ul>
+{{- range .Fields}}
+<li><strong>{{.Label}}</strong>: {{if .Code}}<code>{{.Value}}</code>{{else}}{{.Value}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+{{- if .Ordered}}
+<ol>
+{{- range .Ordered}}
+<li>{{.}}</li>
+{{- end}}
+</ol>
+{{- end}}
+{{- if .Bullets}}
+<ul>
+{{- range .Bullets}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Code}}
+<pre><code>{{range .Code}}{{.}}
+{{end}}</code></pre>
+{{- end}}
+{{- if .Rows}}
+<table>
+<tr><th>{{index .Header 0}}</th><th>{{index .Header 1}}</th></tr>
+{{- range .Rows}}
+<tr><td><code>{{.Left}}</code></td><td>{{.Right}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Examples}}
+<pre><code>{{range .Examples}}{{if .Description}}<span class="comment"># {{.Description}}</span>
+{{end}}{{.Command}}
+{{end}}</code></pre>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+var htmlIndex = template.Must(template.New("index").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html lang="{{lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>` + htmlStyle + `</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr><th>{{t "render.command"}}</th><th>{{t "render.summary"}}</th><th>{{t "render.version"}}</th></tr>
+{{- range .Entries}}
+<tr><td><a href="{{.File}}">{{.Name}}</a></td><td>{{.Summary}}</td><td>{{.Version}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, doc document) error {
+	return htmlDocument.Execute(w, doc)
+}
+
+func writeHTMLIndex(w io.Writer, title string, entries []IndexEntry) error {
+	return htmlIndex.Execute(w, struct {
+		Title   string
+		Entries []IndexEntry
+	}{title, entries})
+}
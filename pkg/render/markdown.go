@@ -0,0 +1,86 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"ghp/pkg/i18n"
+)
+
+func writeMarkdown(w io.Writer, doc document) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n\n", doc.Title)
+	if doc.Note != "" {
+		fmt.Fprintf(bw, "> %s\n\n", doc.Note)
+	}
+	if doc.Summary != "" {
+		fmt.Fprintf(bw, "%s\n\n", doc.Summary)
+	}
+	for _, f := range doc.Fields {
+		value := f.Value
+		if f.Code {
+			value = mdCode(value)
+		}
+		fmt.Fprintf(bw, "- **%s**: %s\n", f.Label, value)
+	}
+	if len(doc.Fields) > 0 {
+		fmt.Fprintln(bw)
+	}
+
+	for _, s := range doc.Sections {
+		fmt.Fprintf(bw, "## %s\n\n", s.Title)
+		for i, item := range s.Ordered {
+			fmt.Fprintf(bw, "%d. %s\n", i+1, item)
+		}
+		for _, item := range s.Bullets {
+			fmt.Fprintf(bw, "- %s\n", item)
+		}
+		if len(s.Code) > 0 {
+			fmt.Fprintf(bw, "```bash\n%s\n```\n", strings.Join(s.Code, "\n"))
+		}
+		if len(s.Rows) > 0 {
+			fmt.Fprintf(bw, "| %s | %s |\n| --- | --- |\n", s.Header[0], s.Header[1])
+			for _, r := range s.Rows {
+				fmt.Fprintf(bw, "| %s | %s |\n", mdCode(mdCell(r.Left)), mdCell(r.Right))
+			}
+		}
+		if len(s.Examples) > 0 {
+			fmt.Fprintln(bw, "```bash")
+			for _, e := range s.Examples {
+				if e.Description != "" {
+					fmt.Fprintf(bw, "# %s\n", e.Description)
+				}
+				fmt.Fprintln(bw, e.Command)
+			}
+			fmt.Fprintln(bw, "```")
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+func writeMarkdownIndex(w io.Writer, title string, entries []IndexEntry) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n\n", title)
+	fmt.Fprintf(bw, "| %s | %s | %s |\n| --- | --- | --- |\n", i18n.T("render.command"), i18n.T("render.summary"), i18n.T("render.version"))
+	for _, e := range entries {
+		fmt.Fprintf(bw, "| [%s](%s) | %s | %s |\n", e.Name, e.File, mdCell(e.Summary), mdCell(e.Version))
+	}
+	return bw.Flush()
+}
+
+// mdCell 转义表格单元格中的竖线和换行
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// mdCode 行内代码
+func mdCode(s string) string {
+	if s == "" {
+		return s
+	}
+	return "`" + s + "`"
+}
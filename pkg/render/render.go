@@ -0,0 +1,311 @@
+// Package render 将结构化回答排版输出到终端
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"ghp/pkg/ai"
+	"ghp/pkg/i18n"
+)
+
+const (
+	defaultWidth = 80
+	indent       = "  "
+	columnGap    = "  "
+)
+
+// Renderer 终端渲染器
+// 输出到终端时使用彩色标题、按终端宽度对齐折行的选项列和高亮的示例命令；
+// 输出被重定向或设置了 NO_COLOR 时退化为不带颜色、不折行的纯文本
+type Renderer struct {
+	w     io.Writer
+	color bool
+	width int // 终端宽度，0 表示不折行
+}
+
+// New 根据输出目标创建渲染器
+func New(f *os.File) *Renderer {
+	r := &Renderer{w: f}
+	if !term.IsTerminal(int(f.Fd())) {
+		return r
+	}
+	r.color = os.Getenv("NO_COLOR") == ""
+	r.width = terminalWidth(f)
+	return r
+}
+
+// terminalWidth 获取终端宽度，依次尝试终端本身、COLUMNS 环境变量和默认值
+func terminalWidth(f *os.File) int {
+	if w, _, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return defaultWidth
+}
+
+// Render 按回答类型排版输出
+func (r *Renderer) Render(answer ai.Answer) {
+	switch a := answer.(type) {
+	case *ai.CheatSheet:
+		r.cheatSheet(a)
+	case *ai.SubcommandCard:
+		r.subcommandCard(a)
+	case *ai.CommandExplanation:
+		r.explanation(a)
+	case *ai.GeneratedCommand:
+		r.generated(a)
+	}
+}
+
+func (r *Renderer) cheatSheet(a *ai.CheatSheet) {
+	if a.Offline {
+		r.offline()
+	}
+	r.field(i18n.T("render.summary"), a.Summary)
+	r.field(i18n.T("render.location"), a.Location)
+	r.field(i18n.T("render.version"), a.Version)
+
+	if len(a.Install) > 0 {
+		r.header(i18n.T("render.install"))
+		for i, line := range a.Install {
+			r.numbered(i+1, clean(line))
+		}
+	}
+	if len(a.Usage) > 0 {
+		r.header(i18n.T("render.usage"))
+		for _, line := range a.Usage {
+			r.CommandLine(clean(line))
+		}
+	}
+	r.options(i18n.T("render.options"), a.Options)
+	r.examples(i18n.T("render.examples"), a.Examples)
+}
+
+func (r *Renderer) subcommandCard(a *ai.SubcommandCard) {
+	switch a.Kind {
+	case ai.SubcommandNaturalLanguage:
+		fmt.Fprintln(r.w, r.paint(styleWarning, i18n.T("render.hint"))+" "+i18n.T("render.natural_language"))
+		fmt.Fprintln(r.w, i18n.T("render.example")+" "+r.highlightCommand(i18n.T("render.example_generate")))
+		return
+	case ai.SubcommandInvalid:
+		fmt.Fprintln(r.w, r.paint(styleError, i18n.T("render.invalid")))
+		return
+	}
+
+	if a.Offline {
+		r.offline()
+	}
+	r.field(i18n.T("render.subcommand"), a.Subcommand)
+	r.field(i18n.T("render.purpose"), a.Summary)
+	r.options(i18n.T("render.options"), a.Options)
+	r.examples(i18n.T("render.subcommand_examples"), a.Examples)
+}
+
+func (r *Renderer) explanation(a *ai.CommandExplanation) {
+	r.risk(a.Risk)
+	r.field(i18n.T("render.command"), a.Command)
+	r.field(i18n.T("render.location"), a.Location)
+	r.parts(a.Parts)
+	if a.Summary != "" {
+		fmt.Fprintln(r.w)
+		r.field(i18n.T("render.conclusion"), a.Summary)
+	}
+	r.list(i18n.T("render.suggestions"), a.Suggestions)
+}
+
+func (r *Renderer) generated(a *ai.GeneratedCommand) {
+	r.risk(a.Risk)
+	r.header(i18n.T("render.generated"))
+	r.CommandLine(clean(a.Command))
+	r.list(i18n.T("render.verification"), verificationNotes(a.Verification))
+	r.parts(a.Parts)
+	r.list(i18n.T("render.suggestions"), a.Suggestions)
+}
+
+// offline 标明回答由本地解析帮助文档生成，并非 AI 回答
+func (r *Renderer) offline() {
+	fmt.Fprintln(r.w, r.paint(styleWarning, i18n.T("render.offline")))
+	fmt.Fprintln(r.w)
+}
+
+// risk 按风险等级着色输出横幅和原因，未评估时不输出
+func (r *Renderer) risk(rk ai.Risk) {
+	if rk.Level == "" {
+		return
+	}
+	fmt.Fprintln(r.w, r.paint(riskStyle(rk.Level), i18n.T("risk.level."+rk.Level)))
+	for _, reason := range riskReasons(rk) {
+		lines := wrap(reason, r.width-displayWidth(indent+"- "))
+		fmt.Fprintln(r.w, indent+"- "+lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintln(r.w, indent+"  "+line)
+		}
+	}
+	fmt.Fprintln(r.w)
+}
+
+// field 输出 "标签: 内容" 形式的单行字段，内容为空时跳过
+func (r *Renderer) field(label, value string) {
+	value = clean(value)
+	if value == "" {
+		return
+	}
+	prefix := label + ": "
+	lines := wrap(value, r.width-displayWidth(prefix))
+	fmt.Fprintln(r.w, r.paint(styleLabel, label+":")+" "+lines[0])
+	for _, line := range lines[1:] {
+		fmt.Fprintln(r.w, strings.Repeat(" ", displayWidth(prefix))+line)
+	}
+}
+
+// header 输出小节标题，与上一节之间空一行
+func (r *Renderer) header(title string) {
+	fmt.Fprintln(r.w)
+	fmt.Fprintln(r.w, r.paint(styleHeader, title+":"))
+}
+
+// CommandLine 输出一行高亮的命令
+func (r *Renderer) CommandLine(cmd string) {
+	fmt.Fprintln(r.w, indent+r.highlightCommand(cmd))
+}
+
+// numbered 输出带序号的一行，如安装方式 "1. Homebrew (推荐): brew install curl"
+func (r *Renderer) numbered(n int, line string) {
+	prefix := strconv.Itoa(n) + ". "
+	if label, cmd, ok := strings.Cut(line, ": "); ok {
+		line = label + ": " + r.highlightCommand(cmd)
+	}
+	fmt.Fprintln(r.w, indent+prefix+line)
+}
+
+func (r *Renderer) options(title string, options []ai.Option) {
+	if len(options) == 0 {
+		return
+	}
+	rows := make([]row, 0, len(options))
+	for _, o := range options {
+		rows = append(rows, row{clean(o.Flag), clean(o.Description)})
+	}
+	r.header(title)
+	r.table(rows, r.highlightFlag, nil)
+}
+
+func (r *Renderer) examples(title string, examples []ai.Example) {
+	if len(examples) == 0 {
+		return
+	}
+	rows := make([]row, 0, len(examples))
+	vetted := false
+	for _, e := range examples {
+		desc := exampleDescription(e)
+		if desc != "" {
+			desc = "# " + desc
+		}
+		rows = append(rows, row{clean(e.Command), desc})
+		vetted = vetted || e.TLDR
+	}
+	r.header(title)
+	r.table(rows, r.highlightCommand, func(s string) string { return r.paint(styleComment, s) })
+	if vetted {
+		fmt.Fprintln(r.w, indent+r.paint(styleComment, i18n.T("render.tldr_note")))
+	}
+}
+
+// tldrTag 来自本地 tldr 页面的示例在说明后附加的标记
+const tldrTag = "[tldr]"
+
+// exampleDescription 示例说明，来自 tldr 页面的示例附加标记，与模型生成的示例区分
+func exampleDescription(e ai.Example) string {
+	desc := clean(e.Description)
+	if e.TLDR {
+		desc = strings.TrimSpace(desc + " " + tldrTag)
+	}
+	return desc
+}
+
+func (r *Renderer) parts(parts []ai.CommandPart) {
+	if len(parts) == 0 {
+		return
+	}
+	rows := make([]row, 0, len(parts))
+	for _, p := range parts {
+		rows = append(rows, row{clean(p.Token), clean(p.Meaning)})
+	}
+	r.header(i18n.T("render.parts"))
+	r.table(rows, r.highlightCommand, nil)
+}
+
+// list 输出带标题的无序列表
+func (r *Renderer) list(title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	r.header(title)
+	for _, s := range items {
+		lines := wrap(clean(s), r.width-displayWidth(indent+"- "))
+		fmt.Fprintln(r.w, indent+"- "+lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintln(r.w, indent+"  "+line)
+		}
+	}
+}
+
+// row 两列表格中的一行
+type row struct {
+	Left, Right string
+}
+
+// table 两列对齐输出，右列按终端宽度折行
+// 左列过宽时右列另起一行缩进输出，避免挤压所有行的说明列
+func (r *Renderer) table(rows []row, left, right func(string) string) {
+	if right == nil {
+		right = func(s string) string { return s }
+	}
+
+	maxLeft := 40
+	if r.width > 0 {
+		maxLeft = r.width * 2 / 5
+	}
+	col := 0
+	for _, row := range rows {
+		if w := displayWidth(row.Left); w > col && w <= maxLeft {
+			col = w
+		}
+	}
+
+	descIndent := indent + strings.Repeat(" ", col) + columnGap
+	descWidth := 0
+	if r.width > 0 {
+		descWidth = r.width - displayWidth(descIndent)
+	}
+	for _, row := range rows {
+		lines := wrap(row.Right, descWidth)
+		if row.Right == "" {
+			lines = nil
+		}
+		if displayWidth(row.Left) > col {
+			fmt.Fprintln(r.w, indent+left(row.Left))
+			for _, line := range lines {
+				fmt.Fprintln(r.w, descIndent+right(line))
+			}
+			continue
+		}
+		if len(lines) == 0 {
+			fmt.Fprintln(r.w, indent+left(row.Left))
+			continue
+		}
+		pad := padRight("", col-displayWidth(row.Left))
+		fmt.Fprintln(r.w, indent+left(row.Left)+pad+columnGap+right(lines[0]))
+		for _, line := range lines[1:] {
+			fmt.Fprintln(r.w, descIndent+right(line))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner 等待 AI 回答时在 stderr 显示的进度提示
+// stderr 不是终端时不输出任何内容，避免污染日志
+type Spinner struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartSpinner 开始显示进度提示
+func StartSpinner(message string) *Spinner {
+	s := &Spinner{}
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		return s
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(os.Stderr, "\r%s %s", spinnerFrames[i%len(spinnerFrames)], message)
+			select {
+			case <-s.stop:
+				fmt.Fprint(os.Stderr, "\r\x1b[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop 停止并清除进度提示，可重复调用
+func (s *Spinner) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+}
@@ -0,0 +1,148 @@
+package render
+
+import (
+	"strings"
+
+	"ghp/pkg/risk"
+)
+
+// ANSI 样式
+const (
+	styleReset       = "\x1b[0m"
+	styleHeader      = "\x1b[1;36m"
+	styleLabel       = "\x1b[1m"
+	styleProgram     = "\x1b[1;32m"
+	styleFlag        = "\x1b[33m"
+	styleString      = "\x1b[32m"
+	stylePlaceholder = "\x1b[35m"
+	styleComment     = "\x1b[90m"
+	styleOperator    = "\x1b[36m"
+	styleWarning     = "\x1b[1;33m"
+	styleError       = "\x1b[1;31m"
+)
+
+// paint 为文本添加样式，未启用颜色时原样返回
+func (r *Renderer) paint(style, s string) string {
+	if !r.color || s == "" {
+		return s
+	}
+	return style + s + styleReset
+}
+
+// riskStyle 风险等级横幅的颜色
+func riskStyle(level string) string {
+	switch level {
+	case risk.Safe:
+		return styleProgram
+	case risk.ModifiesState:
+		return styleWarning
+	}
+	return styleError
+}
+
+// clean 去掉模型习惯性输出的 Markdown 标记
+func clean(s string) string {
+	s = strings.ReplaceAll(s, "**", "")
+	s = strings.ReplaceAll(s, "`", "")
+	return strings.TrimSpace(s)
+}
+
+// shell 操作符，其后的单词视为新的程序名
+var shellOperators = map[string]bool{
+	"|": true, "||": true, "&&": true, ";": true, "&": true,
+	">": false, ">>": false, "<": false, "2>": false, "2>&1": false,
+}
+
+// highlightCommand 为命令添加语法高亮：程序名、选项、引号字符串、<占位符> 和 # 注释
+func (r *Renderer) highlightCommand(cmd string) string {
+	if !r.color {
+		return cmd
+	}
+
+	var sb strings.Builder
+	expectProgram := true
+	for i := 0; i < len(cmd); {
+		c := cmd[i]
+		switch {
+		case c == ' ' || c == '\t':
+			sb.WriteByte(c)
+			i++
+		case c == '#':
+			sb.WriteString(r.paint(styleComment, cmd[i:]))
+			i = len(cmd)
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(cmd[i+1:], c)
+			if end < 0 {
+				end = len(cmd)
+			} else {
+				end += i + 2
+			}
+			sb.WriteString(r.paint(styleString, cmd[i:end]))
+			i = end
+			expectProgram = false
+		case c == '<' && placeholderLen(cmd[i:]) > 0:
+			end := i + placeholderLen(cmd[i:])
+			sb.WriteString(r.paint(stylePlaceholder, cmd[i:end]))
+			i = end
+			expectProgram = false
+		default:
+			end := i
+			for end < len(cmd) && cmd[end] != ' ' && cmd[end] != '\t' && cmd[end] != '"' && cmd[end] != '\'' {
+				end++
+			}
+			word := cmd[i:end]
+			if newCommand, ok := shellOperators[word]; ok {
+				sb.WriteString(r.paint(styleOperator, word))
+				expectProgram = newCommand
+			} else if expectProgram && !strings.Contains(word, "=") {
+				sb.WriteString(r.paint(styleProgram, word))
+				expectProgram = false
+			} else if strings.HasPrefix(word, "-") {
+				sb.WriteString(r.paint(styleFlag, word))
+			} else {
+				sb.WriteString(word)
+			}
+			i = end
+		}
+	}
+	return sb.String()
+}
+
+// placeholderLen 返回开头 <name> 占位符的长度，不是占位符时返回 0
+func placeholderLen(s string) int {
+	end := strings.IndexByte(s, '>')
+	if !strings.HasPrefix(s, "<") || end < 2 || strings.ContainsAny(s[:end], " \t") {
+		return 0
+	}
+	return end + 1
+}
+
+// highlightFlag 为选项写法添加高亮，<占位符> 与选项区分显示
+func (r *Renderer) highlightFlag(flag string) string {
+	if !r.color {
+		return flag
+	}
+
+	var sb strings.Builder
+	for flag != "" {
+		start := strings.IndexAny(flag, "<[")
+		if start < 0 {
+			sb.WriteString(r.paint(styleFlag, flag))
+			break
+		}
+		closing := byte('>')
+		if flag[start] == '[' {
+			closing = ']'
+		}
+		end := strings.IndexByte(flag[start:], closing)
+		if end < 0 {
+			sb.WriteString(r.paint(styleFlag, flag))
+			break
+		}
+		end += start + 1
+		sb.WriteString(r.paint(styleFlag, flag[:start]))
+		sb.WriteString(r.paint(stylePlaceholder, flag[start:end]))
+		flag = flag[end:]
+	}
+	return sb.String()
+}
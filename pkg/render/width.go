@@ -0,0 +1,81 @@
+package render
+
+import (
+	"strings"
+	"unicode"
+)
+
+// runeWidth 字符在终端中占用的列数，中日韩文字及全角符号占两列
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r):
+		return 0
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
+
+// displayWidth 字符串在终端中的显示宽度
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// wrap 按显示宽度折行，优先在空格处断开，中文字符之间可以任意断开
+// width <= 0 时不折行
+func wrap(s string, width int) []string {
+	if width <= 0 || displayWidth(s) <= width {
+		return []string{s}
+	}
+
+	var lines []string
+	runes := []rune(s)
+	start, w, brk := 0, 0, -1
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if i > start && (r == ' ' || runeWidth(r) == 2 || runeWidth(runes[i-1]) == 2) {
+			brk = i
+		}
+		rw := runeWidth(r)
+		if w+rw > width && brk > start {
+			lines = append(lines, strings.TrimRight(string(runes[start:brk]), " "))
+			start = brk
+			for start < len(runes) && runes[start] == ' ' {
+				start++
+			}
+			brk = -1
+			if start > i {
+				i = start - 1
+				w = 0
+				continue
+			}
+			w = displayWidth(string(runes[start:i]))
+		}
+		w += rw
+	}
+	if start < len(runes) {
+		lines = append(lines, string(runes[start:]))
+	}
+	return lines
+}
+
+// padRight 按显示宽度补齐空格
+func padRight(s string, width int) string {
+	if n := width - displayWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
@@ -0,0 +1,132 @@
+// Package risk 评估命令的风险等级，本地规则与模型给出的等级取较高者
+package risk
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ghp/pkg/i18n"
+)
+
+// 风险等级，由低到高
+const (
+	Safe          = "safe"           // 只读，不修改任何状态
+	ModifiesState = "modifies_state" // 会修改文件、配置或远端状态，但可以恢复
+	Destructive   = "destructive"    // 删除或覆盖数据
+	Irreversible  = "irreversible"   // 无法恢复，如格式化磁盘、强制覆盖远端历史
+)
+
+// Levels 所有风险等级，由低到高
+var Levels = []string{Safe, ModifiesState, Destructive, Irreversible}
+
+// Rank 返回风险等级的高低，未知等级返回 -1
+func Rank(level string) int {
+	return slices.Index(Levels, level)
+}
+
+// Max 返回较高的风险等级
+func Max(a, b string) string {
+	if Rank(b) > Rank(a) {
+		return b
+	}
+	return a
+}
+
+// Rule 一条本地规则，命令匹配 Pattern (正则表达式) 时至少为 Level 等级
+type Rule struct {
+	Pattern string `yaml:"pattern"`
+	Level   string `yaml:"level"`
+	Reason  string `yaml:"reason,omitempty"`
+}
+
+// Match 命中的规则
+type Match struct {
+	Level  string
+	Reason string
+}
+
+type compiledRule struct {
+	group  string
+	re     *regexp.Regexp
+	level  string
+	reason string
+}
+
+// devNull 丢弃输出的重定向，不算写文件
+var devNull = regexp.MustCompile(`\d*>>?\s*/dev/null\b`)
+
+// Engine 本地规则引擎，包含内置规则和配置文件中的规则
+type Engine struct {
+	rules []compiledRule
+}
+
+// NewEngine 创建规则引擎，extra 为配置文件中追加的规则
+func NewEngine(extra []Rule) (*Engine, error) {
+	e := &Engine{}
+	for _, r := range builtinRules {
+		e.rules = append(e.rules, compiledRule{group: r.group, re: regexp.MustCompile(r.pattern), level: r.level, reason: i18n.T(r.reason)})
+	}
+	for _, r := range extra {
+		c, err := compile(r)
+		if err != nil {
+			return nil, err
+		}
+		e.rules = append(e.rules, c)
+	}
+	return e, nil
+}
+
+// Validate 校验配置文件中的规则
+func Validate(rules []Rule) error {
+	for _, r := range rules {
+		if _, err := compile(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func compile(r Rule) (compiledRule, error) {
+	if Rank(r.Level) < 0 {
+		return compiledRule{}, errors.New(i18n.T("risk.err_level", r.Level, r.Pattern, strings.Join(Levels, ", ")))
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return compiledRule{}, errors.New(i18n.T("risk.err_pattern", r.Pattern, err))
+	}
+	reason := r.Reason
+	if reason == "" {
+		reason = r.Pattern
+	}
+	return compiledRule{re: re, level: r.Level, reason: reason}, nil
+}
+
+// Check 返回命令命中的规则，多余的空白会先合并为一个空格
+func (e *Engine) Check(command string) []Match {
+	command = strings.Join(strings.Fields(devNull.ReplaceAllString(command, "")), " ")
+	var matches []Match
+	matched := make(map[string]bool)
+	for _, r := range e.rules {
+		if (r.group != "" && matched[r.group]) || !r.re.MatchString(command) {
+			continue
+		}
+		if r.group != "" {
+			matched[r.group] = true
+		}
+		if !slices.ContainsFunc(matches, func(m Match) bool { return m.Reason == r.reason }) {
+			matches = append(matches, Match{Level: r.level, Reason: r.reason})
+		}
+	}
+	return matches
+}
+
+// Level 返回命中规则中的最高风险等级，没有命中时返回空字符串
+func Level(matches []Match) string {
+	level := ""
+	for _, m := range matches {
+		level = Max(level, m.Level)
+	}
+	return level
+}
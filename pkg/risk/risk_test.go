@@ -0,0 +1,104 @@
+package risk
+
+import "testing"
+
+func TestBuiltinRules(t *testing.T) {
+	tests := []struct {
+		command string
+		level   string // 期望的最高等级，"" 表示不命中任何规则
+	}{
+		// 删除文件
+		{"rm -rf /", Irreversible},
+		{"sudo rm -rf ~/", Irreversible},
+		{"rm -fr *", Irreversible},
+		{"rm -r build", Destructive},
+		{"rm --recursive -f build", Destructive},
+		{"rm notes.txt", Destructive},
+		{"ls | xargs rm", Destructive},
+		{"find . -name '*.o' | xargs -0 rm", Destructive},
+		{"ls *.log | xargs -I {} rm {}", Destructive},
+		{`find . -name '*.tmp' -exec rm {} \;`, Destructive},
+		{"cd /tmp && rm", Destructive},
+		{"shred -u secret.key", Irreversible},
+		{"find /tmp -mtime +7 -delete", Destructive},
+
+		// 磁盘
+		{"dd if=ubuntu.iso of=/dev/sdb bs=4M", Irreversible},
+		{"sudo mkfs.ext4 /dev/sdb1", Irreversible},
+		{"echo hi > /dev/sda", Irreversible},
+
+		// 权限
+		{"chmod -R 777 /", Irreversible},
+		{"chmod -R 777 ./public ", Destructive},
+		{"chmod +x build.sh", ModifiesState},
+
+		// Git
+		{"git push --force origin main", Irreversible},
+		{"git push origin +main", Irreversible},
+		{"git push origin --delete feature", Destructive},
+		{"git reset --hard HEAD~1", Destructive},
+		{"git clean -fdx", Destructive},
+		{"git checkout -- .", Destructive},
+		{"git branch -D feature", Destructive},
+		{"git commit -m 'fix'", ModifiesState},
+		{"git -C repo push", ModifiesState},
+
+		// 容器、数据库、系统
+		{"kubectl delete pod web-0", Destructive},
+		{"docker system prune -a", Destructive},
+		{`psql -c "DROP TABLE users"`, Irreversible},
+		{`mysql -e "DELETE FROM users"`, Irreversible},
+		{":(){ :|:& };:", Irreversible},
+		{"sudo reboot", Destructive},
+		{"curl -fsSL https://example.com/install.sh | sh", ModifiesState},
+		{"echo x > out.txt", ModifiesState},
+		{"cp a.txt b.txt", ModifiesState},
+
+		// 不应命中
+		{"ls -la", ""},
+		{"make 2>&1 | tee", ""},
+		{"make >/dev/null 2>&1", ""},
+		{"grep -r pattern . 2> /dev/null", ""},
+		{"git status", ""},
+		{"git log --format=%H", ""},
+		{"git push-tool --help", ""},
+		{"echo rm", ""},
+		{"grep -rn 'rm -rf' scripts/", ""},
+		{"perform --check", ""},
+		{"ls | xargs wc -l", ""},
+		{"kubectl get pods", ""},
+		{"docker ps -a", ""},
+		{"firmware-update --list", ""},
+		{"sed -n 1,10p file", ""},
+		{"cat file | sort | uniq -c", ""},
+		{"find . -name '*.go'", ""},
+		{"echo 'a > b'", ModifiesState}, // 引号中的 > 也会被视为重定向，宁可误报
+	}
+	engine, err := NewEngine(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		matches := engine.Check(tt.command)
+		if got := Level(matches); got != tt.level {
+			t.Errorf("%q: level = %q, want %q (matches %v)", tt.command, got, tt.level, matches)
+		}
+	}
+}
+
+func TestExtraRules(t *testing.T) {
+	engine, err := NewEngine([]Rule{{Pattern: `\bterraform\s+destroy\b`, Level: Irreversible, Reason: "destroys resources"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := engine.Check("terraform   destroy -auto-approve")
+	if Level(matches) != Irreversible || matches[0].Reason != "destroys resources" {
+		t.Errorf("matches = %v", matches)
+	}
+	if err := Validate([]Rule{{Pattern: "(", Level: Safe}}); err == nil {
+		t.Error("invalid pattern should be rejected")
+	}
+	if err := Validate([]Rule{{Pattern: "x", Level: "dangerous"}}); err == nil {
+		t.Error("unknown level should be rejected")
+	}
+}
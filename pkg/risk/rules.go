@@ -0,0 +1,65 @@
+package risk
+
+// 命令的开头: 行首或 管道、&&、;、sudo 等之后
+// xargs 之后可以有选项，如 xargs -0 rm、xargs -I {} rm {}
+const cmdStart = `(?:^|[;&|(]\s*|\bsudo\s+|\bxargs\s+(?:-\S+\s+(?:\{\}\s+)?)*|\bexec\s+)`
+
+// gitCmd git 及其全局选项，-C、-c 带有参数，如 git -C repo push
+const gitCmd = `\bgit\s+(?:-[Cc]\s+\S+\s+|-\S+\s+)*`
+
+// builtinRule 内置规则，reason 为 i18n 文案的 key
+// 同一 group 的规则按风险由高到低排列，只报告命中的第一条
+type builtinRule struct {
+	group   string
+	pattern string
+	level   string
+	reason  string
+}
+
+// builtinRules 内置规则，只用于提高风险等级，宁可误报也不要漏报
+var builtinRules = []builtinRule{
+	// 删除文件
+	{"rm", cmdStart + `rm\s+(?:-\S*\s+)*-\S*[rR]\S*\s+(?:-\S+\s+)*(?:/|/\*|~|~/|\$HOME/?|\*|\.\.?/?)(?:\s|$)`, Irreversible, "risk.rule.rm_root"},
+	{"rm", cmdStart + `rm\s+(?:-\S*\s+)*-\S*[rR]`, Destructive, "risk.rule.rm_recursive"},
+	// 命令可以以 rm 结尾，如 ls | xargs rm
+	{"rm", cmdStart + `rm(?:\s|$)`, Destructive, "risk.rule.rm"},
+	{"", cmdStart + `(?:shred|srm)(?:\s|$)`, Irreversible, "risk.rule.shred"},
+	{"", `\bfind\s.*\s-delete\b`, Destructive, "risk.rule.find_delete"},
+
+	// 磁盘和文件系统
+	{"", cmdStart + `dd\s.*\bof=/dev/`, Irreversible, "risk.rule.dd"},
+	{"", cmdStart + `(?:mkfs(?:\.\w+)?|wipefs|mkswap)\s`, Irreversible, "risk.rule.mkfs"},
+	{"", cmdStart + `(?:fdisk|sfdisk|gdisk|sgdisk|parted)\s`, Irreversible, "risk.rule.partition"},
+	{"redirect", `>\s*/dev/(?:sd|hd|vd|nvme|disk|mmcblk)`, Irreversible, "risk.rule.dev_write"},
+
+	// 权限
+	{"chmod", cmdStart + `(?:chmod|chown|chgrp)\s+(?:-\S+\s+)*-\S*R\S*\s.*\s/(?:\s|$)`, Irreversible, "risk.rule.chmod_root"},
+	{"chmod", cmdStart + `chmod\s+(?:-\S+\s+)*-\S*R\S*\s+(?:-\S+\s+)*0?777\s`, Destructive, "risk.rule.chmod_777"},
+	{"chmod", cmdStart + `(?:chmod|chown|chgrp)\s`, ModifiesState, "risk.rule.chmod"},
+
+	// Git
+	{"git", gitCmd + `push\b.*\s(?:--force\b|-f\b|--force-with-lease\b|\+\S)`, Irreversible, "risk.rule.git_force_push"},
+	{"git", gitCmd + `push\b.*\s(?:--delete|-d)\b`, Destructive, "risk.rule.git_delete_remote"},
+	{"git", gitCmd + `reset\s.*--hard\b`, Destructive, "risk.rule.git_reset_hard"},
+	{"git", gitCmd + `clean\s.*-\S*f`, Destructive, "risk.rule.git_clean"},
+	{"git", gitCmd + `(?:checkout|restore)\b.*\s(?:--\s|\.(?:\s|$))`, Destructive, "risk.rule.git_discard"},
+	{"git", gitCmd + `branch\b.*\s-D\b`, Destructive, "risk.rule.git_branch_delete"},
+	{"git", gitCmd + `(?:push|commit|merge|rebase|reset|stash|tag|branch|checkout|switch|restore|rm|mv|cherry-pick|revert|pull|am|apply)(?:\s|$)`, ModifiesState, "risk.rule.git_write"},
+
+	// 容器与集群
+	{"", `\bkubectl\s.*\bdelete\b`, Destructive, "risk.rule.kubectl_delete"},
+	{"", `\b(?:docker|podman)\s+(?:system|volume|image|container|network)\s+prune\b`, Destructive, "risk.rule.docker_prune"},
+	{"", `\b(?:docker|podman)\s+(?:rm|rmi|volume\s+rm)\b`, Destructive, "risk.rule.docker_rm"},
+
+	// 数据库
+	{"", `(?i)\b(?:drop\s+(?:table|database|schema)|truncate\s+table)\b`, Irreversible, "risk.rule.sql_drop"},
+	{"", `(?i)\bdelete\s+from\s+\w+\s*(?:;|$|")`, Irreversible, "risk.rule.sql_delete_all"},
+
+	// 系统
+	{"", `:\(\)\s*\{\s*:\s*\|\s*:\s*&\s*\}\s*;\s*:`, Irreversible, "risk.rule.fork_bomb"},
+	{"", cmdStart + `(?:shutdown|reboot|poweroff|halt)\b`, Destructive, "risk.rule.shutdown"},
+	{"", `\b(?:curl|wget)\s[^|]*\|\s*(?:sudo\s+)?(?:ba|z|da)?sh\b`, ModifiesState, "risk.rule.pipe_shell"},
+	{"redirect", `(?:^|[^>&0-9])>\s*[^\s&>|]`, ModifiesState, "risk.rule.redirect"},
+	{"", cmdStart + `(?:mv|cp)\s`, ModifiesState, "risk.rule.overwrite"},
+	{"", cmdStart + `sudo\s`, ModifiesState, "risk.rule.sudo"},
+}
@@ -0,0 +1,147 @@
+// Package tldr 读取本地的 tldr 页面 (tldr 客户端的离线缓存或 tldr-pages 仓库)
+// 页面中的示例经过社区审核，作为模型生成常用示例的依据，读取时不需要联网
+package tldr
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Page 一个 tldr 页面
+type Page struct {
+	Name        string
+	Description string // 简介，不含 "More information" 链接
+	Examples    []Example
+	Path        string // 页面文件
+}
+
+// Example 一条示例，命令中的 {{占位符}} 已转为 <占位符>
+type Example struct {
+	Description string
+	Command     string
+}
+
+var (
+	// optionPlaceholder 同时给出短选项和长选项的写法，如 {{[-o|--output]}}，取长选项
+	optionPlaceholder = regexp.MustCompile(`\{\{\[([^|\]]*)\|([^\]]*)\]\}\}`)
+	placeholder       = regexp.MustCompile(`\{\{(.*?)\}\}`)
+	link              = regexp.MustCompile(`<https?://[^>]*>`)
+	// mnemonic 说明中标注短选项来源的字母，如 "List [a]ll files"
+	mnemonic = regexp.MustCompile(`\[(\w)\]`)
+)
+
+// Find 在本地查找页面，name 为程序名或 "程序-子命令" (如 git-commit)，找不到时返回 nil
+// language 为回答语言，优先使用该语言的页面，没有时使用英文页面；dir 为配置中指定的 tldr-pages 目录，可以为空
+func Find(name, language, dir string) *Page {
+	name = strings.ToLower(filepath.Base(name))
+	if name == "" || name == "." {
+		return nil
+	}
+	for _, root := range roots(dir) {
+		for _, pages := range pageDirs(language) {
+			for _, platform := range platforms() {
+				path := filepath.Join(root, pages, platform, name+".md")
+				data, err := os.ReadFile(path)
+				if err != nil {
+					continue
+				}
+				if page := Parse(string(data)); len(page.Examples) > 0 {
+					page.Path = path
+					return page
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// roots 可能存在 tldr 页面的目录，其下为 pages、pages.zh 等按语言划分的子目录
+func roots(dir string) []string {
+	var dirs []string
+	home, homeErr := os.UserHomeDir()
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok && homeErr == nil {
+		dir = filepath.Join(home, rest)
+	}
+	if dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if cache, err := os.UserCacheDir(); err == nil {
+		dirs = append(dirs,
+			filepath.Join(cache, "tlrc"),                    // tlrc (官方 Rust 客户端)
+			filepath.Join(cache, "tealdeer", "tldr-pages"),  // tealdeer
+			filepath.Join(cache, "tealdeer", "tldr-master"), // 旧版 tealdeer
+			filepath.Join(cache, "tldr"),                    // Python 客户端
+		)
+	}
+	if homeErr == nil {
+		dirs = append(dirs, filepath.Join(home, ".tldr", "cache")) // Node.js 客户端
+	}
+	return dirs
+}
+
+// pageDirs 语言对应的页面目录，英文页面在旧的缓存中位于 pages，新的位于 pages.en
+func pageDirs(language string) []string {
+	var dirs []string
+	if language != "" && language != "en" {
+		dirs = append(dirs, "pages."+language)
+	}
+	return append(dirs, "pages.en", "pages")
+}
+
+// platforms 当前系统对应的页面分类，系统专属的页面优先
+func platforms() []string {
+	switch runtime.GOOS {
+	case "linux":
+		return []string{"linux", "common"}
+	case "darwin":
+		return []string{"osx", "common"}
+	case "windows":
+		return []string{"windows", "common"}
+	}
+	return []string{"common"}
+}
+
+// Parse 解析 tldr 页面的 Markdown
+//
+//	# tar
+//	> 归档工具。
+//	- 创建归档:
+//	`tar cf {{target.tar}} {{file1}}`
+func Parse(text string) *Page {
+	page := &Page{}
+	var desc []string
+	pending := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "# "):
+			page.Name = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, ">"):
+			// 跳过 "More information: <https://...>." 这样的链接行
+			if d := strings.TrimSpace(line[1:]); d != "" && !link.MatchString(d) {
+				desc = append(desc, d)
+			}
+		case strings.HasPrefix(line, "- "):
+			pending = description(strings.TrimRight(strings.TrimSpace(line[2:]), ":："))
+		case strings.HasPrefix(line, "`") && strings.HasSuffix(line, "`") && len(line) > 1:
+			page.Examples = append(page.Examples, Example{Description: pending, Command: command(line[1 : len(line)-1])})
+			pending = ""
+		}
+	}
+	page.Description = strings.Join(desc, " ")
+	return page
+}
+
+// description 去掉说明中的助记标记和占位符括号
+func description(s string) string {
+	return placeholder.ReplaceAllString(mnemonic.ReplaceAllString(s, "$1"), "$1")
+}
+
+// command 将 tldr 的占位符写法转为 ghp 示例中使用的 <占位符>
+func command(s string) string {
+	s = optionPlaceholder.ReplaceAllString(s, "$2")
+	return placeholder.ReplaceAllString(s, "<$1>")
+}
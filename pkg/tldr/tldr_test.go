@@ -0,0 +1,164 @@
+package tldr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		file        string
+		name        string
+		description string
+		examples    []Example
+	}{
+		{
+			file:        "tar.md",
+			name:        "tar",
+			description: "Archiving utility. Often combined with a compression method, such as `gzip` or `bzip2`.",
+			examples: []Example{
+				{"create an archive and write it to a file", "tar cf <path/to/target.tar> <path/to/file1 path/to/file2 ...>"},
+				{"Extract a (compressed) archive file into the current directory verbosely", "tar xvf <path/to/source.tar[.gz|.bz2|.xz]>"},
+				{"Extract an archive into a directory", "tar xf <path/to/source.tar> --directory <path/to/directory>"},
+				{"List the contents of a tar file", "tar tvf <path/to/source.tar>"},
+			},
+		},
+		{
+			file:        "tar.zh.md",
+			name:        "tar",
+			description: "归档工具。",
+			examples: []Example{
+				{"将 文件 归档到 tar 文件", "tar cf <目标.tar> <文件1 文件2 ...>"},
+				{"列出归档中的文件", "tar --list --file <源.tar>"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			page := Parse(string(data))
+			if page.Name != tt.name {
+				t.Errorf("Name = %q, want %q", page.Name, tt.name)
+			}
+			if page.Description != tt.description {
+				t.Errorf("Description = %q, want %q", page.Description, tt.description)
+			}
+			if !reflect.DeepEqual(page.Examples, tt.examples) {
+				t.Errorf("Examples = %q\nwant %q", page.Examples, tt.examples)
+			}
+		})
+	}
+}
+
+// setupRoots 使用临时的主目录和缓存目录，返回两者
+func setupRoots(t *testing.T) (home, cache string) {
+	t.Helper()
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return home, cache
+}
+
+// writePage 在 dir 下写入一个只有一条示例的页面，以简介区分页面来源
+func writePage(t *testing.T, dir, name, from string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	text := "# " + name + "\n\n> " + from + "\n\n- Run:\n\n`" + name + "`\n"
+	if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindRootOrder(t *testing.T) {
+	home, cache := setupRoots(t)
+	// 按查找顺序排列：配置的目录、tlrc、tealdeer、旧版 tealdeer、Python 客户端、Node.js 客户端
+	roots := []string{
+		filepath.Join(home, "tldr-pages"),
+		filepath.Join(cache, "tlrc"),
+		filepath.Join(cache, "tealdeer", "tldr-pages"),
+		filepath.Join(cache, "tealdeer", "tldr-master"),
+		filepath.Join(cache, "tldr"),
+		filepath.Join(home, ".tldr", "cache"),
+	}
+	// 从后往前逐个添加页面，每次都应找到刚添加的页面
+	for i := len(roots) - 1; i >= 0; i-- {
+		writePage(t, filepath.Join(roots[i], "pages", "common"), "tar", roots[i])
+		page := Find("tar", "en", "~/tldr-pages")
+		if page == nil || page.Description != roots[i] {
+			t.Fatalf("找到 %+v, want %s", page, roots[i])
+		}
+		if want := filepath.Join(roots[i], "pages", "common", "tar.md"); page.Path != want {
+			t.Errorf("Path = %q, want %q", page.Path, want)
+		}
+	}
+	// 未配置目录时从客户端缓存中查找
+	if page := Find("tar", "en", ""); page == nil || page.Description != roots[1] {
+		t.Errorf("未配置目录时找到 %+v, want %s", page, roots[1])
+	}
+}
+
+func TestFindLanguageAndPlatform(t *testing.T) {
+	_, cache := setupRoots(t)
+	root := filepath.Join(cache, "tlrc")
+	platform := platforms()[0]
+	// 按查找顺序排列：回答语言优先于英文，同一语言中系统专属的页面优先
+	var dirs []string
+	for _, pages := range []string{"pages.zh", "pages.en", "pages"} {
+		for _, p := range platforms() {
+			dirs = append(dirs, filepath.Join(root, pages, p))
+		}
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		writePage(t, dirs[i], "tar", dirs[i])
+		if page := Find("tar", "zh", ""); page == nil || page.Description != dirs[i] {
+			t.Fatalf("找到 %+v, want %s", page, dirs[i])
+		}
+	}
+	// 英文不查找 pages.en 以外的语言目录
+	if page := Find("tar", "en", ""); page == nil || page.Description != filepath.Join(root, "pages.en", platform) {
+		t.Errorf("英文找到 %+v", page)
+	}
+}
+
+func TestFindName(t *testing.T) {
+	_, cache := setupRoots(t)
+	common := filepath.Join(cache, "tlrc", "pages", "common")
+	writePage(t, common, "git-commit", "git commit")
+	// 没有示例的页面不使用，继续在后面的目录中查找
+	if err := os.WriteFile(filepath.Join(common, "empty.md"), []byte("# empty\n\n> No examples.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writePage(t, filepath.Join(cache, "tldr", "pages", "common"), "empty", "python")
+
+	tests := []struct {
+		name string
+		want string // 期望页面的简介，为空表示找不到
+	}{
+		{"git-commit", "git commit"},
+		{"/usr/bin/GIT-Commit", "git commit"},
+		{"empty", "python"},
+		{"missing", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		page := Find(tt.name, "en", "")
+		got := ""
+		if page != nil {
+			got = page.Description
+		}
+		if got != tt.want {
+			t.Errorf("Find(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package verify 将模型生成的命令与程序真实的帮助文档比对，找出帮助文档中不存在的选项和子命令
+package verify
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ghp/pkg/helpparse"
+)
+
+// Inventory 从帮助文档中提取的选项和子命令清单
+type Inventory struct {
+	Flags       map[string]bool // 选项，包含前缀，如 -a、--all
+	Args        map[string]bool // 需要参数值的选项，如 -o <file>
+	OptArgs     map[string]bool // 参数可省略的选项，参数只能紧跟在选项后，如 -i.bak、--color=auto
+	Subcommands map[string]bool
+	// PartialSubcommands 帮助文档只列出常用的子命令 (如 git、docker、kubectl)，未列出的子命令无法判定是否存在
+	PartialSubcommands bool
+}
+
+// partialCommands 说明子命令列表不完整的文字，如 git 的 "These are common Git commands"、
+// docker 的 "Common Commands:"、kubectl 的 "Basic Commands (Beginner):"，以及指向完整列表的 "help -a"
+var partialCommands = regexp.MustCompile(`(?i)\b(?:common(?:ly used)?|basic|popular|frequently used|some|additional|more|other)\b[\w ]{0,20}\bcommands\b|\bhelp -a\b|--help-all\b|\blist (?:of )?all\b[\w ]{0,20}\bcommands\b`)
+
+// NewInventory 解析帮助文档，生成选项和子命令清单
+func NewInventory(help string) *Inventory {
+	inv := &Inventory{
+		Flags:       make(map[string]bool),
+		Args:        make(map[string]bool),
+		OptArgs:     make(map[string]bool),
+		Subcommands: make(map[string]bool),
+		// 子命令列表不完整时，未列出的子命令只提示无法校验，不请模型修正
+		PartialSubcommands: partialCommands.MatchString(help),
+	}
+	h := helpparse.Parse(help)
+	for _, f := range h.Flags {
+		for _, name := range f.Names {
+			inv.Flags[name] = true
+			if f.Arg != "" {
+				inv.Args[name] = !f.OptionalArg
+				inv.OptArgs[name] = f.OptionalArg
+			}
+		}
+	}
+	for _, sub := range h.Subcommands {
+		for _, name := range sub.Names {
+			inv.Subcommands[name] = true
+		}
+	}
+	return inv
+}
+
+// wrappers 不影响校验的前置命令
+var wrappers = []string{"sudo", "env", "time", "nohup", "command", "exec"}
+
+// Check 返回命令中在帮助文档里找不到的选项和子命令 (unknown)，
+// 以及帮助文档只列出常用子命令时，未列出因而无法判定的子命令 (unverified)
+// 只检查以 program 开头的命令段，管道后的其他程序不做检查
+func (inv *Inventory) Check(program, command string) (unknown, unverified []string) {
+	program = filepath.Base(program)
+	add := func(list *[]string, word string) {
+		if !slices.Contains(*list, word) {
+			*list = append(*list, word)
+		}
+	}
+
+	for _, seg := range segments(splitWords(command)) {
+		if len(seg) == 0 || filepath.Base(seg[0]) != program {
+			continue
+		}
+		args := seg[1:]
+		for i := 0; i < len(args); i++ {
+			arg := args[i]
+			if arg == "--" {
+				break
+			}
+			if strings.HasPrefix(arg, "-") && arg != "-" {
+				if !inv.knownFlag(arg) {
+					add(&unknown, arg)
+				} else if inv.Args[arg] {
+					i++ // 跳过选项的参数值
+				}
+				continue
+			}
+			// 有子命令的程序，第一个位置参数为子命令，之后的选项属于子命令，不在顶层帮助文档中
+			if len(inv.Subcommands) == 0 {
+				continue
+			}
+			switch {
+			case inv.Subcommands[arg]:
+			case inv.PartialSubcommands:
+				add(&unverified, arg)
+			default:
+				add(&unknown, arg)
+			}
+			break
+		}
+	}
+	return unknown, unverified
+}
+
+// knownFlag 选项是否出现在帮助文档中
+func (inv *Inventory) knownFlag(arg string) bool {
+	name, _, _ := strings.Cut(arg, "=")
+	if inv.Flags[name] {
+		return true
+	}
+	if strings.HasPrefix(name, "--") {
+		return inv.knownAlias(name) || inv.uniquePrefix(name)
+	}
+	// 数字参数，如 head -5，或负数参数值
+	if strings.Trim(name[1:], "0123456789.") == "" {
+		return true
+	}
+	// 组合的单字母选项，如 -xzv；需要参数的选项之后的部分为参数值，如 -n5
+	for _, r := range name[1:] {
+		short := "-" + string(r)
+		if !inv.Flags[short] {
+			return false
+		}
+		if inv.Args[short] || inv.OptArgs[short] {
+			return true
+		}
+	}
+	return true
+}
+
+// spellings GNU 工具通常同时接受英式和美式拼写，帮助文档只列出其中一种，如 ls --colour 与 --color
+var spellings = strings.NewReplacer(
+	"colour", "color", "color", "colour",
+	"behaviour", "behavior", "behavior", "behaviour",
+	"licence", "license", "license", "licence",
+	"centre", "center", "center", "centre",
+	"normalise", "normalize", "normalize", "normalise",
+	"initialise", "initialize", "initialize", "initialise",
+	"summarise", "summarize", "summarize", "summarise",
+)
+
+// knownAlias 长选项换用另一种拼写后是否出现在帮助文档中
+func (inv *Inventory) knownAlias(name string) bool {
+	alias := spellings.Replace(name)
+	return alias != name && inv.Flags[alias]
+}
+
+// uniquePrefix GNU getopt 允许使用长选项的唯一前缀，如 --verb 表示 --verbose
+func (inv *Inventory) uniquePrefix(name string) bool {
+	matches := 0
+	for flag := range inv.Flags {
+		if strings.HasPrefix(flag, name) && strings.HasPrefix(flag, "--") {
+			matches++
+		}
+	}
+	return matches == 1
+}
+
+// segments 按控制符将单词拆分为多条命令，并去掉开头的环境变量赋值和前置命令
+func segments(words []string) [][]string {
+	var segs [][]string
+	var cur []string
+	for _, w := range words {
+		if isOperator(w) {
+			segs = append(segs, cur)
+			cur = nil
+			continue
+		}
+		if len(cur) == 0 && (slices.Contains(wrappers, w) || isAssignment(w)) {
+			continue
+		}
+		cur = append(cur, w)
+	}
+	return append(segs, cur)
+}
+
+// isAssignment 是否为 NAME=value 形式的环境变量赋值
+func isAssignment(word string) bool {
+	name, _, ok := strings.Cut(word, "=")
+	if !ok || name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
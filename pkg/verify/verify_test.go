@@ -0,0 +1,101 @@
+package verify
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		help       string // testdata 中的帮助文档
+		program    string
+		command    string
+		unknown    []string
+		unverified []string
+	}{
+		// git --help 只列出常用子命令，未列出的子命令不能判为不存在
+		{"git listed subcommand", "git.txt", "git", "git commit -m 'msg'", nil, nil},
+		{"git unlisted subcommand", "git.txt", "git", "git config --global user.name x", nil, []string{"config"}},
+		{"git global option before subcommand", "git.txt", "git", "git -C repo --no-pager log", nil, nil},
+		{"git unknown global option", "git.txt", "git", "git --frobnicate status", []string{"--frobnicate"}, nil},
+
+		// cobra 的 Available Commands 是完整列表
+		{"cobra listed subcommand", "cobra.txt", "hugo", "hugo server -D", nil, nil},
+		{"cobra unknown subcommand", "cobra.txt", "hugo", "hugo deploy", []string{"deploy"}, nil},
+		{"cobra flag with value", "cobra.txt", "hugo", "hugo -d public --minify", nil, nil},
+		{"cobra unknown flag", "cobra.txt", "hugo", "hugo --watch", []string{"--watch"}, nil},
+
+		// GNU getopt: 英式拼写、唯一前缀、组合短选项、需要参数的选项
+		{"gnu british spelling", "ls.txt", "ls", "ls --colour=auto", nil, nil},
+		{"gnu american spelling", "ls.txt", "ls", "ls --color=never -la", nil, nil},
+		{"gnu unique prefix", "ls.txt", "ls", "ls --human /tmp", nil, nil},
+		{"gnu ambiguous prefix", "ls.txt", "ls", "ls --si --hi", []string{"--hi"}, nil},
+		{"gnu short cluster", "ls.txt", "ls", "ls -lhtr", nil, nil},
+		{"gnu unknown short in cluster", "ls.txt", "ls", "ls -ly", []string{"-ly"}, nil},
+		{"gnu option argument", "ls.txt", "ls", "ls -w 80 -I '*.o'", nil, nil},
+		{"gnu unknown long", "ls.txt", "ls", "ls --colourful", []string{"--colourful"}, nil},
+		{"gnu positional args", "ls.txt", "ls", "ls -- -weird-name", nil, nil},
+
+		// 命令段: 只检查以 program 开头的部分
+		{"other program in pipe", "ls.txt", "ls", "ls -1 | grep --frob x", nil, nil},
+		{"sudo and env prefix", "ls.txt", "ls", "sudo LC_ALL=C ls --bogus", []string{"--bogus"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile("testdata/" + tt.help)
+			if err != nil {
+				t.Fatal(err)
+			}
+			unknown, unverified := NewInventory(string(data)).Check(tt.program, tt.command)
+			if !slices.Equal(unknown, tt.unknown) {
+				t.Errorf("unknown = %q, want %q", unknown, tt.unknown)
+			}
+			if !slices.Equal(unverified, tt.unverified) {
+				t.Errorf("unverified = %q, want %q", unverified, tt.unverified)
+			}
+		})
+	}
+}
+
+func TestPartialSubcommands(t *testing.T) {
+	tests := []struct {
+		help    string
+		partial bool
+	}{
+		{"git.txt", true},
+		{"cobra.txt", false},
+		{"ls.txt", false},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile("testdata/" + tt.help)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := NewInventory(string(data)).PartialSubcommands; got != tt.partial {
+			t.Errorf("%s: PartialSubcommands = %v, want %v", tt.help, got, tt.partial)
+		}
+	}
+}
+
+func TestPartialSubcommandHeaders(t *testing.T) {
+	tests := []struct {
+		header  string
+		partial bool
+	}{
+		{"Common Commands:", true},                    // docker
+		{"Basic Commands (Beginner):", true},          // kubectl
+		{"See 'git help -a' for the full list", true}, // git
+		{"Most commonly used commands:", true},
+		{"Commands:", false},
+		{"Available Commands:", false},
+		{"Management Commands:", false},
+	}
+	for _, tt := range tests {
+		help := "Usage: tool [command]\n\n" + tt.header + "\n  run   Run a thing\n"
+		if got := NewInventory(help).PartialSubcommands; got != tt.partial {
+			t.Errorf("%q: PartialSubcommands = %v, want %v", tt.header, got, tt.partial)
+		}
+	}
+}
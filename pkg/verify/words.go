@@ -0,0 +1,67 @@
+package verify
+
+import "strings"
+
+// splitWords 按 Shell 规则将命令行拆分为单词，处理单双引号和反斜杠转义
+// 管道、&&、||、; 等控制符单独作为一个单词返回，以便按命令分段
+// 不展开变量和命令替换，只用于静态检查
+func splitWords(cmdline string) []string {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	flush := func() {
+		if inWord {
+			words = append(words, cur.String())
+			cur.Reset()
+			inWord = false
+		}
+	}
+
+	runes := []rune(cmdline)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			cur.WriteRune(runes[i])
+			inWord = true
+		case r == '\'':
+			inWord = true
+			for i++; i < len(runes) && runes[i] != '\''; i++ {
+				cur.WriteRune(runes[i])
+			}
+		case r == '"':
+			inWord = true
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`+"`", runes[i+1]) {
+					i++
+				}
+				cur.WriteRune(runes[i])
+			}
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		case r == '|' || r == '&' || r == ';':
+			flush()
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == r && r != ';' {
+				op += string(r)
+				i++
+			}
+			words = append(words, op)
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	flush()
+	return words
+}
+
+// isOperator 是否为分隔命令的控制符
+func isOperator(word string) bool {
+	switch word {
+	case "|", "||", "&", "&&", ";":
+		return true
+	}
+	return false
+}